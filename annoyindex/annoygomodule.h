@@ -53,6 +53,9 @@ class AnnoyIndex {
   int getNItems() {
     return (int)ptr->get_n_items();
   };
+  int getNTrees() {
+    return (int)ptr->get_n_trees();
+  };
   void verbose(bool v) {
     ptr->verbose(v);
   };
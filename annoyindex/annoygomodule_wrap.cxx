@@ -623,6 +623,19 @@ intgo _wrap_AnnoyIndex_getNItems_annoyindex_270be72e18d030db(GoAnnoy::AnnoyIndex
 }
 
 
+intgo _wrap_AnnoyIndex_getNTrees_annoyindex_270be72e18d030db(GoAnnoy::AnnoyIndex *_swig_go_0) {
+  GoAnnoy::AnnoyIndex *arg1 = (GoAnnoy::AnnoyIndex *) 0 ;
+  int result;
+  intgo _swig_go_result;
+  
+  arg1 = *(GoAnnoy::AnnoyIndex **)&_swig_go_0; 
+  
+  result = (int)(arg1)->getNTrees();
+  _swig_go_result = result; 
+  return _swig_go_result;
+}
+
+
 void _wrap_AnnoyIndex_verbose_annoyindex_270be72e18d030db(GoAnnoy::AnnoyIndex *_swig_go_0, bool _swig_go_1) {
   GoAnnoy::AnnoyIndex *arg1 = (GoAnnoy::AnnoyIndex *) 0 ;
   bool arg2 ;
@@ -1076,6 +1089,20 @@ intgo _wrap_AnnoyIndexAngular_getNItems_annoyindex_270be72e18d030db(GoAnnoy::Ann
 }
 
 
+intgo _wrap_AnnoyIndexAngular_getNTrees_annoyindex_270be72e18d030db(GoAnnoy::AnnoyIndexAngular *_swig_go_0) {
+  GoAnnoy::AnnoyIndexAngular *arg1 = (GoAnnoy::AnnoyIndexAngular *) 0 ;
+  int result;
+  intgo _swig_go_result;
+  
+  arg1 = *(GoAnnoy::AnnoyIndexAngular **)&_swig_go_0; 
+  
+  GoAnnoy::AnnoyIndex *swig_b0 = (GoAnnoy::AnnoyIndex *)arg1;
+  result = (int)(swig_b0)->getNTrees();
+  _swig_go_result = result; 
+  return _swig_go_result;
+}
+
+
 void _wrap_AnnoyIndexAngular_verbose_annoyindex_270be72e18d030db(GoAnnoy::AnnoyIndexAngular *_swig_go_0, bool _swig_go_1) {
   GoAnnoy::AnnoyIndexAngular *arg1 = (GoAnnoy::AnnoyIndexAngular *) 0 ;
   bool arg2 ;
@@ -1521,6 +1548,20 @@ intgo _wrap_AnnoyIndexEuclidean_getNItems_annoyindex_270be72e18d030db(GoAnnoy::A
 }
 
 
+intgo _wrap_AnnoyIndexEuclidean_getNTrees_annoyindex_270be72e18d030db(GoAnnoy::AnnoyIndexEuclidean *_swig_go_0) {
+  GoAnnoy::AnnoyIndexEuclidean *arg1 = (GoAnnoy::AnnoyIndexEuclidean *) 0 ;
+  int result;
+  intgo _swig_go_result;
+  
+  arg1 = *(GoAnnoy::AnnoyIndexEuclidean **)&_swig_go_0; 
+  
+  GoAnnoy::AnnoyIndex *swig_b0 = (GoAnnoy::AnnoyIndex *)arg1;
+  result = (int)(swig_b0)->getNTrees();
+  _swig_go_result = result; 
+  return _swig_go_result;
+}
+
+
 void _wrap_AnnoyIndexEuclidean_verbose_annoyindex_270be72e18d030db(GoAnnoy::AnnoyIndexEuclidean *_swig_go_0, bool _swig_go_1) {
   GoAnnoy::AnnoyIndexEuclidean *arg1 = (GoAnnoy::AnnoyIndexEuclidean *) 0 ;
   bool arg2 ;
@@ -1966,6 +2007,20 @@ intgo _wrap_AnnoyIndexManhattan_getNItems_annoyindex_270be72e18d030db(GoAnnoy::A
 }
 
 
+intgo _wrap_AnnoyIndexManhattan_getNTrees_annoyindex_270be72e18d030db(GoAnnoy::AnnoyIndexManhattan *_swig_go_0) {
+  GoAnnoy::AnnoyIndexManhattan *arg1 = (GoAnnoy::AnnoyIndexManhattan *) 0 ;
+  int result;
+  intgo _swig_go_result;
+  
+  arg1 = *(GoAnnoy::AnnoyIndexManhattan **)&_swig_go_0; 
+  
+  GoAnnoy::AnnoyIndex *swig_b0 = (GoAnnoy::AnnoyIndex *)arg1;
+  result = (int)(swig_b0)->getNTrees();
+  _swig_go_result = result; 
+  return _swig_go_result;
+}
+
+
 void _wrap_AnnoyIndexManhattan_verbose_annoyindex_270be72e18d030db(GoAnnoy::AnnoyIndexManhattan *_swig_go_0, bool _swig_go_1) {
   GoAnnoy::AnnoyIndexManhattan *arg1 = (GoAnnoy::AnnoyIndexManhattan *) 0 ;
   bool arg2 ;
@@ -2411,6 +2466,20 @@ intgo _wrap_AnnoyIndexDotProduct_getNItems_annoyindex_270be72e18d030db(GoAnnoy::
 }
 
 
+intgo _wrap_AnnoyIndexDotProduct_getNTrees_annoyindex_270be72e18d030db(GoAnnoy::AnnoyIndexDotProduct *_swig_go_0) {
+  GoAnnoy::AnnoyIndexDotProduct *arg1 = (GoAnnoy::AnnoyIndexDotProduct *) 0 ;
+  int result;
+  intgo _swig_go_result;
+  
+  arg1 = *(GoAnnoy::AnnoyIndexDotProduct **)&_swig_go_0; 
+  
+  GoAnnoy::AnnoyIndex *swig_b0 = (GoAnnoy::AnnoyIndex *)arg1;
+  result = (int)(swig_b0)->getNTrees();
+  _swig_go_result = result; 
+  return _swig_go_result;
+}
+
+
 void _wrap_AnnoyIndexDotProduct_verbose_annoyindex_270be72e18d030db(GoAnnoy::AnnoyIndexDotProduct *_swig_go_0, bool _swig_go_1) {
   GoAnnoy::AnnoyIndexDotProduct *arg1 = (GoAnnoy::AnnoyIndexDotProduct *) 0 ;
   bool arg2 ;
@@ -60,6 +60,7 @@ extern void _wrap_AnnoyIndex_getNnsByVector__SWIG_0_annoyindex_270be72e18d030db(
 extern void _wrap_AnnoyIndex_getNnsByItem__SWIG_1_annoyindex_270be72e18d030db(uintptr_t arg1, swig_intgo arg2, swig_intgo arg3, swig_intgo arg4, swig_voidp arg5);
 extern void _wrap_AnnoyIndex_getNnsByVector__SWIG_1_annoyindex_270be72e18d030db(uintptr_t arg1, swig_type_7 arg2, swig_intgo arg3, swig_intgo arg4, swig_voidp arg5);
 extern swig_intgo _wrap_AnnoyIndex_getNItems_annoyindex_270be72e18d030db(uintptr_t arg1);
+extern swig_intgo _wrap_AnnoyIndex_getNTrees_annoyindex_270be72e18d030db(uintptr_t arg1);
 extern void _wrap_AnnoyIndex_verbose_annoyindex_270be72e18d030db(uintptr_t arg1, _Bool arg2);
 extern void _wrap_AnnoyIndex_getItem_annoyindex_270be72e18d030db(uintptr_t arg1, swig_intgo arg2, swig_voidp arg3);
 extern _Bool _wrap_AnnoyIndex_onDiskBuild_annoyindex_270be72e18d030db(uintptr_t arg1, swig_type_8 arg2);
@@ -79,6 +80,7 @@ extern void _wrap_AnnoyIndexAngular_getNnsByItem__SWIG_1_annoyindex_270be72e18d0
 extern void _wrap_AnnoyIndexAngular_getNnsByVector__SWIG_0_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_type_14 arg1, swig_intgo arg2, swig_intgo arg3, swig_voidp arg4, swig_voidp arg5);
 extern void _wrap_AnnoyIndexAngular_getNnsByVector__SWIG_1_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_type_15 arg1, swig_intgo arg2, swig_intgo arg3, swig_voidp arg4);
 extern swig_intgo _wrap_AnnoyIndexAngular_getNItems_annoyindex_270be72e18d030db(uintptr_t _swig_base);
+extern swig_intgo _wrap_AnnoyIndexAngular_getNTrees_annoyindex_270be72e18d030db(uintptr_t _swig_base);
 extern void _wrap_AnnoyIndexAngular_verbose_annoyindex_270be72e18d030db(uintptr_t _swig_base, _Bool arg1);
 extern void _wrap_AnnoyIndexAngular_getItem_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_intgo arg1, swig_voidp arg2);
 extern _Bool _wrap_AnnoyIndexAngular_onDiskBuild_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_type_16 arg1);
@@ -97,6 +99,7 @@ extern void _wrap_AnnoyIndexEuclidean_getNnsByItem__SWIG_1_annoyindex_270be72e18
 extern void _wrap_AnnoyIndexEuclidean_getNnsByVector__SWIG_0_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_type_14 arg1, swig_intgo arg2, swig_intgo arg3, swig_voidp arg4, swig_voidp arg5);
 extern void _wrap_AnnoyIndexEuclidean_getNnsByVector__SWIG_1_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_type_15 arg1, swig_intgo arg2, swig_intgo arg3, swig_voidp arg4);
 extern swig_intgo _wrap_AnnoyIndexEuclidean_getNItems_annoyindex_270be72e18d030db(uintptr_t _swig_base);
+extern swig_intgo _wrap_AnnoyIndexEuclidean_getNTrees_annoyindex_270be72e18d030db(uintptr_t _swig_base);
 extern void _wrap_AnnoyIndexEuclidean_verbose_annoyindex_270be72e18d030db(uintptr_t _swig_base, _Bool arg1);
 extern void _wrap_AnnoyIndexEuclidean_getItem_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_intgo arg1, swig_voidp arg2);
 extern _Bool _wrap_AnnoyIndexEuclidean_onDiskBuild_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_type_16 arg1);
@@ -115,6 +118,7 @@ extern void _wrap_AnnoyIndexManhattan_getNnsByItem__SWIG_1_annoyindex_270be72e18
 extern void _wrap_AnnoyIndexManhattan_getNnsByVector__SWIG_0_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_type_14 arg1, swig_intgo arg2, swig_intgo arg3, swig_voidp arg4, swig_voidp arg5);
 extern void _wrap_AnnoyIndexManhattan_getNnsByVector__SWIG_1_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_type_15 arg1, swig_intgo arg2, swig_intgo arg3, swig_voidp arg4);
 extern swig_intgo _wrap_AnnoyIndexManhattan_getNItems_annoyindex_270be72e18d030db(uintptr_t _swig_base);
+extern swig_intgo _wrap_AnnoyIndexManhattan_getNTrees_annoyindex_270be72e18d030db(uintptr_t _swig_base);
 extern void _wrap_AnnoyIndexManhattan_verbose_annoyindex_270be72e18d030db(uintptr_t _swig_base, _Bool arg1);
 extern void _wrap_AnnoyIndexManhattan_getItem_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_intgo arg1, swig_voidp arg2);
 extern _Bool _wrap_AnnoyIndexManhattan_onDiskBuild_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_type_16 arg1);
@@ -133,6 +137,7 @@ extern void _wrap_AnnoyIndexDotProduct_getNnsByItem__SWIG_1_annoyindex_270be72e1
 extern void _wrap_AnnoyIndexDotProduct_getNnsByVector__SWIG_0_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_type_14 arg1, swig_intgo arg2, swig_intgo arg3, swig_voidp arg4, swig_voidp arg5);
 extern void _wrap_AnnoyIndexDotProduct_getNnsByVector__SWIG_1_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_type_15 arg1, swig_intgo arg2, swig_intgo arg3, swig_voidp arg4);
 extern swig_intgo _wrap_AnnoyIndexDotProduct_getNItems_annoyindex_270be72e18d030db(uintptr_t _swig_base);
+extern swig_intgo _wrap_AnnoyIndexDotProduct_getNTrees_annoyindex_270be72e18d030db(uintptr_t _swig_base);
 extern void _wrap_AnnoyIndexDotProduct_verbose_annoyindex_270be72e18d030db(uintptr_t _swig_base, _Bool arg1);
 extern void _wrap_AnnoyIndexDotProduct_getItem_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_intgo arg1, swig_voidp arg2);
 extern _Bool _wrap_AnnoyIndexDotProduct_onDiskBuild_annoyindex_270be72e18d030db(uintptr_t _swig_base, swig_type_16 arg1);
@@ -360,6 +365,13 @@ func (arg1 SwigcptrAnnoyIndex) GetNItems() (_swig_ret int) {
 	return swig_r
 }
 
+func (arg1 SwigcptrAnnoyIndex) GetNTrees() (_swig_ret int) {
+	var swig_r int
+	_swig_i_0 := arg1
+	swig_r = (int)(C._wrap_AnnoyIndex_getNTrees_annoyindex_270be72e18d030db(C.uintptr_t(_swig_i_0)))
+	return swig_r
+}
+
 func (arg1 SwigcptrAnnoyIndex) Verbose(arg2 bool) {
 	_swig_i_0 := arg1
 	_swig_i_1 := arg2
@@ -402,6 +414,7 @@ type AnnoyIndex interface {
 	GetNnsByItem(a ...interface{})
 	GetNnsByVector(a ...interface{})
 	GetNItems() (_swig_ret int)
+	GetNTrees() (_swig_ret int)
 	Verbose(arg2 bool)
 	GetItem(arg2 int, arg3 *[]float32)
 	OnDiskBuild(arg2 string) (_swig_ret bool)
@@ -590,6 +603,12 @@ func (_swig_base SwigcptrAnnoyIndexAngular) GetNItems() (_swig_ret int) {
 	return swig_r
 }
 
+func (_swig_base SwigcptrAnnoyIndexAngular) GetNTrees() (_swig_ret int) {
+	var swig_r int
+	swig_r = (int)(C._wrap_AnnoyIndexAngular_getNTrees_annoyindex_270be72e18d030db(C.uintptr_t(_swig_base)))
+	return swig_r
+}
+
 func (_swig_base SwigcptrAnnoyIndexAngular) Verbose(arg1 bool) {
 	_swig_i_0 := arg1
 	C._wrap_AnnoyIndexAngular_verbose_annoyindex_270be72e18d030db(C.uintptr_t(_swig_base), C._Bool(_swig_i_0))
@@ -630,6 +649,7 @@ type AnnoyIndexAngular interface {
 	GetNnsByItem(a ...interface{})
 	GetNnsByVector(a ...interface{})
 	GetNItems() (_swig_ret int)
+	GetNTrees() (_swig_ret int)
 	Verbose(arg1 bool)
 	GetItem(arg1 int, arg2 *[]float32)
 	OnDiskBuild(arg1 string) (_swig_ret bool)
@@ -820,6 +840,12 @@ func (_swig_base SwigcptrAnnoyIndexEuclidean) GetNItems() (_swig_ret int) {
 	return swig_r
 }
 
+func (_swig_base SwigcptrAnnoyIndexEuclidean) GetNTrees() (_swig_ret int) {
+	var swig_r int
+	swig_r = (int)(C._wrap_AnnoyIndexEuclidean_getNTrees_annoyindex_270be72e18d030db(C.uintptr_t(_swig_base)))
+	return swig_r
+}
+
 func (_swig_base SwigcptrAnnoyIndexEuclidean) Verbose(arg1 bool) {
 	_swig_i_0 := arg1
 	C._wrap_AnnoyIndexEuclidean_verbose_annoyindex_270be72e18d030db(C.uintptr_t(_swig_base), C._Bool(_swig_i_0))
@@ -860,6 +886,7 @@ type AnnoyIndexEuclidean interface {
 	GetNnsByItem(a ...interface{})
 	GetNnsByVector(a ...interface{})
 	GetNItems() (_swig_ret int)
+	GetNTrees() (_swig_ret int)
 	Verbose(arg1 bool)
 	GetItem(arg1 int, arg2 *[]float32)
 	OnDiskBuild(arg1 string) (_swig_ret bool)
@@ -1050,6 +1077,12 @@ func (_swig_base SwigcptrAnnoyIndexManhattan) GetNItems() (_swig_ret int) {
 	return swig_r
 }
 
+func (_swig_base SwigcptrAnnoyIndexManhattan) GetNTrees() (_swig_ret int) {
+	var swig_r int
+	swig_r = (int)(C._wrap_AnnoyIndexManhattan_getNTrees_annoyindex_270be72e18d030db(C.uintptr_t(_swig_base)))
+	return swig_r
+}
+
 func (_swig_base SwigcptrAnnoyIndexManhattan) Verbose(arg1 bool) {
 	_swig_i_0 := arg1
 	C._wrap_AnnoyIndexManhattan_verbose_annoyindex_270be72e18d030db(C.uintptr_t(_swig_base), C._Bool(_swig_i_0))
@@ -1090,6 +1123,7 @@ type AnnoyIndexManhattan interface {
 	GetNnsByItem(a ...interface{})
 	GetNnsByVector(a ...interface{})
 	GetNItems() (_swig_ret int)
+	GetNTrees() (_swig_ret int)
 	Verbose(arg1 bool)
 	GetItem(arg1 int, arg2 *[]float32)
 	OnDiskBuild(arg1 string) (_swig_ret bool)
@@ -1280,6 +1314,12 @@ func (_swig_base SwigcptrAnnoyIndexDotProduct) GetNItems() (_swig_ret int) {
 	return swig_r
 }
 
+func (_swig_base SwigcptrAnnoyIndexDotProduct) GetNTrees() (_swig_ret int) {
+	var swig_r int
+	swig_r = (int)(C._wrap_AnnoyIndexDotProduct_getNTrees_annoyindex_270be72e18d030db(C.uintptr_t(_swig_base)))
+	return swig_r
+}
+
 func (_swig_base SwigcptrAnnoyIndexDotProduct) Verbose(arg1 bool) {
 	_swig_i_0 := arg1
 	C._wrap_AnnoyIndexDotProduct_verbose_annoyindex_270be72e18d030db(C.uintptr_t(_swig_base), C._Bool(_swig_i_0))
@@ -1320,6 +1360,7 @@ type AnnoyIndexDotProduct interface {
 	GetNnsByItem(a ...interface{})
 	GetNnsByVector(a ...interface{})
 	GetNItems() (_swig_ret int)
+	GetNTrees() (_swig_ret int)
 	Verbose(arg1 bool)
 	GetItem(arg1 int, arg2 *[]float32)
 	OnDiskBuild(arg1 string) (_swig_ret bool)
@@ -1,10 +1,6 @@
 package data
 
 import (
-	"sort"
-	"time"
-
-	"github.com/bgokden/go-cache"
 	"github.com/bgokden/veri/util"
 	pb "github.com/bgokden/veri/veriservice"
 	"github.com/jinzhu/copier"
@@ -16,23 +12,46 @@ type AggregatorInterface interface {
 	Result() []*pb.ScoredDatum
 }
 
+// Aggregator merges results in a keyed TopK, keys of results are de-duplicated by their best score
+// Grouped aggregators keep an aggregator of size GroupLimit for every group label
 type Aggregator struct {
-	Config           *pb.SearchConfig
-	List             []*pb.ScoredDatum
-	DeDuplicationMap *cache.Cache
-	Grouped          bool
-	Context          *pb.SearchContext
-	ScoreFunc        func(arr1 []float32, arr2 []float32) float64
+	TopK
+	Config          *pb.SearchConfig
+	Groups          map[string]AggregatorInterface
+	Grouped         bool
+	Context         *pb.SearchContext
+	ScoreFunc       func(arr1 []float32, arr2 []float32) float64
+	Filter          *Filter
+	ScoreExpression *ScoreExpression
+	Cursor          *Cursor
+	Exclusion       *Exclusion
+	Root            bool
 }
 
 func NewAggrator(config *pb.SearchConfig, grouped bool, context *pb.SearchContext) AggregatorInterface {
 	a := &Aggregator{
-		Config:    config,
-		Grouped:   grouped,
-		Context:   context,
-		ScoreFunc: GetVectorComparisonFunction(config.ScoreFuncName),
+		TopK:            NewKeyedTopK(config.Limit, config.HigherIsBetter),
+		Config:          config,
+		Grouped:         grouped,
+		Context:         context,
+		ScoreFunc:       GetVectorComparisonFunction(config.ScoreFuncName),
+		Filter:          getSearchFilter(config),
+		ScoreExpression: getSearchScoreExpression(config),
+		Cursor:          getSearchCursor(config),
+		Exclusion:       GetExclusion(context),
+	}
+	a.TieBreak = config.Deterministic
+	if grouped {
+		a.Groups = make(map[string]AggregatorInterface)
 	}
-	a.DeDuplicationMap = cache.New(5*time.Minute, 1*time.Minute)
+	return a
+}
+
+// NewRootAggrator creates the aggregator of the final merge of a search
+// Only its result is diversified and cut to ResultLimit, intermediate merges keep every candidate for it
+func NewRootAggrator(config *pb.SearchConfig, grouped bool, context *pb.SearchContext) AggregatorInterface {
+	a := NewAggrator(config, grouped, context).(*Aggregator)
+	a.Root = true
 	return a
 }
 
@@ -46,20 +65,36 @@ func (a *Aggregator) IsNewScoredBetter(old, new float64) bool {
 			return true
 		}
 	} else {
-		if old < new {
+		if new < old {
 			return true
 		}
 	}
 	return false
 }
 
+// BestScore returns the score of a datum against the query and context datums
+// With a score expression or negative datums the vector score is rescored so that every merge gives the same score
 func (a *Aggregator) BestScore(scoredDatum *pb.ScoredDatum) float64 {
+	if a.ScoreExpression != nil {
+		return a.ScoreExpression.Score(a.bestVectorScore(scoredDatum, scoredDatum.GetVectorScore())-a.negativeScore(scoredDatum), scoredDatum.GetDatum(), a.Config.HigherIsBetter)
+	}
+	if len(a.Context.GetNegativeDatum()) > 0 {
+		return a.bestVectorScore(scoredDatum, scoredDatum.GetVectorScore()) - a.negativeScore(scoredDatum)
+	}
+	return a.bestVectorScore(scoredDatum, scoredDatum.GetScore())
+}
+
+func (a *Aggregator) negativeScore(scoredDatum *pb.ScoredDatum) float64 {
+	return NegativeScore(a.Context, a.ScoreFunc, a.Config.HigherIsBetter, scoredDatum.GetDatum().GetKey().GetFeature())
+}
+
+func (a *Aggregator) bestVectorScore(scoredDatum *pb.ScoredDatum, score float64) float64 {
 	if a.Context != nil && len(a.Context.GetDatum()) > 0 {
 		var isSet = false
 		var current float64
 		// When Context is prioritized search score is ignored.
 		if !a.Context.Prioritize {
-			current = scoredDatum.GetScore()
+			current = score
 			isSet = true
 		}
 		for _, datum := range a.Context.GetDatum() {
@@ -72,47 +107,45 @@ func (a *Aggregator) BestScore(scoredDatum *pb.ScoredDatum) float64 {
 
 		return current
 	}
-	return scoredDatum.GetScore()
+	return score
 }
 
-func (a *Aggregator) InsertToList(scoredDatum *pb.ScoredDatum) error {
-	itemAdded := false
-	limit := a.Config.Limit
-	if uint32(len(a.List)) < limit {
-		a.List = append(a.List, scoredDatum)
-		itemAdded = true
-	} else if a.IsNewScoredBetter(a.List[len(a.List)-1].Score, scoredDatum.Score) {
-		a.List[len(a.List)-1] = scoredDatum
-		itemAdded = true
-	}
-	if itemAdded {
-		if a.Config.HigherIsBetter {
-			sort.Slice(a.List, func(i, j int) bool {
-				return a.List[i].Score > a.List[j].Score
-			})
-		} else {
-			sort.Slice(a.List, func(i, j int) bool {
-				return a.List[i].Score < a.List[j].Score
-			})
-		}
-		itemAdded = false
-	}
+func (a *Aggregator) InsertToList(keyString string, scoredDatum *pb.ScoredDatum) error {
+	a.PushKey(keyString, scoredDatum)
 	return nil
 }
 
 func (a *Aggregator) Insert(scoredDatum *pb.ScoredDatum) error {
+	if a.Grouped && len(scoredDatum.GetMembers()) > 0 {
+		// A group of a source is grouped again with the members of other sources
+		for _, member := range scoredDatum.GetMembers() {
+			a.Insert(member)
+		}
+		return nil
+	}
+	if !a.Filter.Matches(scoredDatum.GetDatum()) {
+		return nil // Sources may not support the filter
+	}
+	if a.Exclusion.Excludes(scoredDatum.GetDatum()) {
+		return nil
+	}
+	if a.Config.RadiusSearch && !WithinRadius(a.Config.HigherIsBetter, a.Config.Radius, scoredDatum.GetVectorScore()) {
+		return nil
+	}
 	scoredDatum.Score = a.BestScore(scoredDatum)
+	if !a.Cursor.AfterDatum(scoredDatum, a.Config.HigherIsBetter) {
+		return nil // returned on a previous page
+	}
 	if a.Grouped {
 		keyString := util.EncodeToString(scoredDatum.Datum.Key.GroupLabel)
-		if aGroupAggregatorInterface, ok := a.DeDuplicationMap.Get(keyString); ok {
-			aGroupAggregator := aGroupAggregatorInterface.(AggregatorInterface)
+		if aGroupAggregator, ok := a.Groups[keyString]; ok {
 			return aGroupAggregator.Insert(scoredDatum)
 		} else {
 			var aConfig pb.SearchConfig
 			copier.Copy(&aConfig, a.Config)
 			aConfig.Limit = a.Config.GroupLimit // TODO: find a better solution.
 			aGroupAggregator := NewAggrator(&aConfig, false, nil)
-			a.DeDuplicationMap.Set(keyString, aGroupAggregator, cache.NoExpiration)
+			a.Groups[keyString] = aGroupAggregator
 			return aGroupAggregator.Insert(scoredDatum)
 		}
 	} else {
@@ -120,60 +153,46 @@ func (a *Aggregator) Insert(scoredDatum *pb.ScoredDatum) error {
 		if err != nil {
 			return err
 		}
-		keyString := util.EncodeToString(keyByte)
-		if previousScore, ok := a.DeDuplicationMap.Get(keyString); ok {
-			if a.IsNewScoredBetter(previousScore.(float64), scoredDatum.GetScore()) {
-				a.DeDuplicationMap.Set(keyString, scoredDatum.GetScore(), cache.NoExpiration)
-				return a.InsertToList(scoredDatum)
-			}
-		} else {
-			a.DeDuplicationMap.Set(keyString, scoredDatum.GetScore(), cache.NoExpiration)
-			return a.InsertToList(scoredDatum)
-		}
-
+		return a.InsertToList(util.EncodeToString(keyByte), scoredDatum)
 	}
-	return nil
 }
 
 func (a *Aggregator) Result() []*pb.ScoredDatum {
 	if a.Grouped {
-		groupMap := a.DeDuplicationMap.Items()
-		agg := NewAggrator(a.Config, false, a.Context)
-		for _, groupAggObject := range groupMap {
-			groupAgg := groupAggObject.Object.(AggregatorInterface)
-			agg.Insert(groupAgg.One())
+		agg := NewAggrator(a.Config, false, a.Context).(*Aggregator)
+		agg.Root = a.Root
+		for keyString, groupAgg := range a.Groups {
+			// Members are scored by Insert already, the group score is not rescored
+			if group := groupAgg.One(); group != nil {
+				agg.InsertToList(keyString, group)
+			}
 		}
 		return agg.Result()
 	} else {
-		if a.Config.ResultLimit > 0 && len(a.List) > int(a.Config.ResultLimit) {
-			return a.List[:a.Config.ResultLimit]
+		list := a.Sorted()
+		if !a.Root {
+			return list
 		}
-		return a.List
+		list = Diversify(list, a.Config)
+		if a.Config.ResultLimit > 0 && len(list) > int(a.Config.ResultLimit) {
+			return list[:a.Config.ResultLimit]
+		}
+		return list
 	}
 }
 
+// One returns the best datum of the list with the group score of the list and the list as members
+// The group score is final, it aggregates member scores which include the context and the score expression
+// Vector scores of the members are aggregated the same way
 func (a *Aggregator) One() *pb.ScoredDatum {
 	if len(a.List) > 0 {
-		if a.Config.HigherIsBetter {
-			sumScore := float64(0)
-			for _, scored := range a.List {
-				sumScore += scored.Score
-			}
-			return &pb.ScoredDatum{
-				Score: sumScore,
-				Datum: a.List[0].Datum,
-			}
-		} else {
-			sumScore := float64(0)
-			for _, scored := range a.List {
-				sumScore += scored.Score
-			}
-			floatLen := float64(len(a.List))
-			sumScore = sumScore / (floatLen * floatLen)
-			return &pb.ScoredDatum{
-				Score: sumScore,
-				Datum: a.List[0].Datum,
-			}
+		members := a.Sorted()
+		score, vectorScore := GroupScores(members, a.Config)
+		return &pb.ScoredDatum{
+			Score:       score,
+			VectorScore: vectorScore,
+			Datum:       members[0].Datum,
+			Members:     members,
 		}
 	}
 	return nil
@@ -0,0 +1,246 @@
+package data
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/bgokden/veri/annoyindex"
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+const (
+	AnnoyMetricAngular    = "Angular"
+	AnnoyMetricEuclidean  = "Euclidean"
+	AnnoyMetricManhattan  = "Manhattan"
+	AnnoyMetricDotProduct = "DotProduct"
+)
+
+// annoyMetricOfScoreFunc maps annoy score functions to the index metric answering them
+var annoyMetricOfScoreFunc = map[string]string{
+	"AnnoyVectorDistance":       AnnoyMetricEuclidean,
+	"AnnoyCosineSimilarity":     AnnoyMetricAngular,
+	"AnnoyAngularDistance":      AnnoyMetricAngular,
+	"AnnoyQuickVectorDistance":  AnnoyMetricManhattan,
+	"AnnoyVectorMultiplication": AnnoyMetricDotProduct,
+}
+
+// AnnoyIndex is the common method set of annoy indexes of every metric
+type AnnoyIndex interface {
+	AddItem(arg1 int, arg2 []float32)
+	Build(arg1 int)
+	Unload()
+	GetNnsByVector(a ...interface{})
+	GetNItems() (_swig_ret int)
+	GetNTrees() (_swig_ret int)
+	OnDiskBuild(arg1 string) (_swig_ret bool)
+}
+
+// AnnoyMetricIndex is an annoy index of a metric with its build file
+type AnnoyMetricIndex struct {
+	Metric        string
+	Index         AnnoyIndex
+	BuildFileName string
+	deleteIndex   func()
+}
+
+// NewAnnoyMetricIndex creates an on disk annoy index for the metric
+func NewAnnoyMetricIndex(metric string, dim int) *AnnoyMetricIndex {
+	ai := &AnnoyMetricIndex{
+		Metric: metric,
+	}
+	switch metric {
+	case AnnoyMetricEuclidean:
+		index := annoyindex.NewAnnoyIndexEuclidean(dim)
+		ai.Index = index
+		ai.deleteIndex = func() { annoyindex.DeleteAnnoyIndexEuclidean(index) }
+	case AnnoyMetricManhattan:
+		index := annoyindex.NewAnnoyIndexManhattan(dim)
+		ai.Index = index
+		ai.deleteIndex = func() { annoyindex.DeleteAnnoyIndexManhattan(index) }
+	case AnnoyMetricDotProduct:
+		index := annoyindex.NewAnnoyIndexDotProduct(dim)
+		ai.Index = index
+		ai.deleteIndex = func() { annoyindex.DeleteAnnoyIndexDotProduct(index) }
+	default:
+		index := annoyindex.NewAnnoyIndexAngular(dim)
+		ai.Metric = AnnoyMetricAngular
+		ai.Index = index
+		ai.deleteIndex = func() { annoyindex.DeleteAnnoyIndexAngular(index) }
+	}
+	tmpfile, err := ioutil.TempFile("", "annoy")
+	if err == nil {
+		ai.BuildFileName = tmpfile.Name()
+		tmpfile.Close()
+		ai.Index.OnDiskBuild(ai.BuildFileName)
+	}
+	return ai
+}
+
+// Delete unloads the index and removes its build file
+func (ai *AnnoyMetricIndex) Delete() {
+	ai.Index.Unload() // Not sure if this is needed
+	ai.deleteIndex()
+	if len(ai.BuildFileName) > 0 {
+		os.Remove(ai.BuildFileName)
+	}
+}
+
+// GetAnnoyTrees returns number of trees to build, -1 lets annoy decide
+func GetAnnoyTrees(config *pb.DataConfig) int {
+	if config.GetAnnoyTrees() <= 0 {
+		return -1
+	}
+	return int(config.GetAnnoyTrees())
+}
+
+// GetSearchK returns search_k of annoy for a query, -1 lets annoy use n * trees
+// SearchK has priority over Precision which is the fraction of items to inspect
+func GetSearchK(config *pb.SearchConfig, candidateCount int, nItems int) int {
+	if config.GetSearchK() != 0 {
+		return int(config.GetSearchK())
+	}
+	if config.GetPrecision() > 0 {
+		return max(candidateCount, int(config.GetPrecision()*float64(nItems)))
+	}
+	return -1
+}
+
+func isAnnoyMetric(metric string) bool {
+	switch metric {
+	case AnnoyMetricAngular, AnnoyMetricEuclidean, AnnoyMetricManhattan, AnnoyMetricDotProduct:
+		return true
+	}
+	return false
+}
+
+// GetIndexMetrics returns the annoy metrics to build for a data config
+// Angular is the default when nothing is configured
+func GetIndexMetrics(config *pb.DataConfig) []string {
+	metrics := make([]string, 0, len(config.GetIndexMetrics()))
+	for _, metric := range config.GetIndexMetrics() {
+		if !isAnnoyMetric(metric) {
+			log.Printf("Unknown index metric %v for data %v\n", metric, config.GetName())
+			continue
+		}
+		metrics = append(metrics, metric)
+	}
+	if len(metrics) == 0 {
+		metrics = append(metrics, AnnoyMetricAngular)
+	}
+	return metrics
+}
+
+// AnnoyIndexSet is an offline index with an annoy index per configured metric
+// Items are identified by their position in DataIndex
+type AnnoyIndexSet struct {
+	Config    *pb.DataConfig
+	Indexes   map[string]*AnnoyMetricIndex
+	DataIndex []*DBMapEntry
+	Trees     int
+	built     bool
+}
+
+// NewAnnoyIndexSet creates an empty annoy index set, metric indexes are created with the first item
+func NewAnnoyIndexSet(config *pb.DataConfig) *AnnoyIndexSet {
+	return &AnnoyIndexSet{
+		Config: config,
+	}
+}
+
+// Add adds an item to every metric index, key is not used since annoy can not delete
+func (a *AnnoyIndexSet) Add(key string, feature []float32, entry *DBMapEntry) error {
+	if a.built {
+		return errors.New("Annoy index is already built")
+	}
+	if a.Indexes == nil {
+		a.Indexes = make(map[string]*AnnoyMetricIndex)
+		for _, metric := range GetIndexMetrics(a.Config) {
+			a.Indexes[metric] = NewAnnoyMetricIndex(metric, len(feature))
+		}
+	}
+	i := len(a.DataIndex)
+	for _, metricIndex := range a.Indexes {
+		metricIndex.Index.AddItem(i, feature)
+	}
+	a.DataIndex = append(a.DataIndex, entry)
+	return nil
+}
+
+// Delete is not supported by annoy, deletes are masked by the delta buffer until the next build
+func (a *AnnoyIndexSet) Delete(key string) {}
+
+// Build builds trees of every metric index
+func (a *AnnoyIndexSet) Build() error {
+	trees := GetAnnoyTrees(a.Config)
+	for _, metricIndex := range a.Indexes {
+		start := time.Now()
+		metricIndex.Index.Build(trees) // -1 creates index dynamically
+		log.Printf("Building annoy %v index with %v trees took %s", metricIndex.Metric, metricIndex.Index.GetNTrees(), time.Since(start))
+		a.Trees = metricIndex.Index.GetNTrees()
+	}
+	a.built = true
+	return nil
+}
+
+// Search queries the metric index matching the score function
+func (a *AnnoyIndexSet) Search(feature []float32, n int, config *pb.SearchConfig) []*DBMapEntry {
+	metricIndex := a.GetAnnoyIndex(config.ScoreFuncName)
+	if metricIndex == nil || !a.built {
+		return nil
+	}
+	var result []int
+	var distances []float32
+	searchK := GetSearchK(config, n, metricIndex.Index.GetNItems())
+	metricIndex.Index.GetNnsByVector(feature, n, searchK, &result, &distances)
+	entries := make([]*DBMapEntry, 0, len(result))
+	for _, i := range result {
+		if i >= 0 && i < len(a.DataIndex) && a.DataIndex[i] != nil {
+			entries = append(entries, a.DataIndex[i])
+		}
+	}
+	return entries
+}
+
+// Serves is true if an index is built for the metric of the score function
+func (a *AnnoyIndexSet) Serves(scoreFuncName string) bool {
+	return a.GetAnnoyIndex(scoreFuncName) != nil
+}
+
+// Len is the number of indexed items
+func (a *AnnoyIndexSet) Len() int {
+	return len(a.DataIndex)
+}
+
+// Compact does nothing, deleted entries are dropped by the rebuild in Process
+func (a *AnnoyIndexSet) Compact() {}
+
+// Online is false, annoy indexes are rebuilt in Process
+func (a *AnnoyIndexSet) Online() bool {
+	return false
+}
+
+// Type is Annoy
+func (a *AnnoyIndexSet) Type() string {
+	return IndexTypeAnnoy
+}
+
+// Close deletes every metric index
+func (a *AnnoyIndexSet) Close() {
+	for _, metricIndex := range a.Indexes {
+		metricIndex.Delete()
+	}
+	a.Indexes = nil
+}
+
+// GetAnnoyIndex returns the index matching the score function
+// It is nil if the metric of the score function is not built, neighbours of another metric would be wrong candidates
+func (a *AnnoyIndexSet) GetAnnoyIndex(scoreFuncName string) *AnnoyMetricIndex {
+	metric, ok := annoyMetricOfScoreFunc[scoreFuncName]
+	if !ok {
+		return nil
+	}
+	return a.Indexes[metric]
+}
@@ -0,0 +1,110 @@
+package data
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+// ErrInvalidPageToken is returned when a page token can not be decoded
+var ErrInvalidPageToken = errors.New("Invalid page token")
+
+// Cursor is the end of a search page, datums after it are on the next pages
+// Keys are map keys of datums with the boundary score that are already returned
+type Cursor struct {
+	Score float64  `json:"score"`
+	Keys  [][]byte `json:"keys"`
+	seen  map[string]struct{}
+}
+
+// ParseCursor decodes a page token, an empty token is nil
+func ParseCursor(token string) (*Cursor, error) {
+	if len(token) == 0 {
+		return nil, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	cursor := &Cursor{}
+	if err := json.Unmarshal(decoded, cursor); err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	cursor.seen = make(map[string]struct{}, len(cursor.Keys))
+	for _, key := range cursor.Keys {
+		cursor.seen[string(key)] = struct{}{}
+	}
+	return cursor, nil
+}
+
+// getSearchCursor returns the cursor of a search config
+// An invalid token is rejected before the search, here the search starts from the first page
+func getSearchCursor(config *pb.SearchConfig) *Cursor {
+	cursor, err := ParseCursor(config.GetPageToken())
+	if err != nil {
+		log.Printf("Page token error: %v\n", err)
+		return nil
+	}
+	return cursor
+}
+
+// Token encodes the cursor as a page token
+func (cur *Cursor) Token() string {
+	encoded, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// After is true if a datum with score and map key is not on the pages before the cursor
+// A nil cursor is the start of the first page
+func (cur *Cursor) After(score float64, mapKey string, higherIsBetter bool) bool {
+	if cur == nil {
+		return true
+	}
+	if score == cur.Score {
+		_, seen := cur.seen[mapKey]
+		return !seen
+	}
+	if higherIsBetter {
+		return score < cur.Score
+	}
+	return score > cur.Score
+}
+
+// AfterDatum is After for the map key of datum
+func (cur *Cursor) AfterDatum(scoredDatum *pb.ScoredDatum, higherIsBetter bool) bool {
+	if cur == nil {
+		return true
+	}
+	keyByte, err := GetKeyAsBytes(scoredDatum.GetDatum())
+	if err != nil {
+		return false
+	}
+	return cur.After(scoredDatum.GetScore(), GetMapKey(keyByte), higherIsBetter)
+}
+
+// NextCursor returns the cursor at the end of page which follows previous
+// Keys of previous are kept if the boundary score does not change
+func NextCursor(page []*pb.ScoredDatum, previous *Cursor) *Cursor {
+	if len(page) == 0 {
+		return previous
+	}
+	cursor := &Cursor{
+		Score: page[len(page)-1].GetScore(),
+		Keys:  make([][]byte, 0),
+	}
+	if previous != nil && previous.Score == cursor.Score {
+		cursor.Keys = append(cursor.Keys, previous.Keys...)
+	}
+	for _, scoredDatum := range page {
+		if scoredDatum.GetScore() != cursor.Score {
+			continue
+		}
+		if keyByte, err := GetKeyAsBytes(scoredDatum.GetDatum()); err == nil {
+			cursor.Keys = append(cursor.Keys, []byte(GetMapKey(keyByte)))
+		}
+	}
+	return cursor
+}
@@ -1,10 +1,12 @@
 package data
 
 import (
+	"context"
 	"errors"
 	"log"
 	"os"
 	"path"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -12,26 +14,28 @@ import (
 
 	"github.com/bgokden/go-cache"
 
-	"github.com/bgokden/veri/annoyindex"
-
 	pb "github.com/bgokden/veri/veriservice"
 )
 
 type DataSource interface {
-	StreamSearch(datumList *pb.Datum, scoredDatumStream chan<- *pb.ScoredDatum, queryWaitGroup *sync.WaitGroup, config *pb.SearchConfig) error
+	StreamSearch(datumList *pb.Datum, scoredDatumStream chan<- *pb.ScoredDatum, queryWaitGroup *sync.WaitGroup, config *pb.SearchConfig, context *pb.SearchContext) error
 	Insert(datum *pb.Datum, config *pb.InsertConfig) error
+	InsertBatch(datumList []*pb.InsertDatumWithConfig) ([]*pb.InsertStatus, error)
+	Remove(request *pb.DeleteRequest) (uint64, error)
+	MultiGet(keyList []*pb.DatumKey) ([]*pb.GetResponse, error)
+	StreamData(ctx context.Context, datumStream chan<- *pb.Datum, config *pb.StreamConfig) error
 	GetDataInfo() *pb.DataInfo
 	GetID() string
 }
 
 type Annoyer struct {
 	sync.RWMutex
-	DataIndex     *[]*DBMapEntry
-	AnnoyIndex    annoyindex.AnnoyIndexAngular
-	BuildFileName string
+	Index         Index
+	BuildDuration time.Duration
 }
 
 // Data represents a dataset with similar struture
+// The lock guards the statistics written by Process and the Dirty, Alive and Initialized flags
 type Data struct {
 	sync.RWMutex
 	Config      *pb.DataConfig
@@ -41,7 +45,7 @@ type Data struct {
 	MaxDistance float64
 	Hist        []float32
 	Timestamp   uint64
-	// DB          *badger.DB
+	Store       *Store
 	DBPath      string
 	Dirty       bool
 	Sources     *cache.Cache
@@ -49,14 +53,44 @@ type Data struct {
 	Initialized bool
 	Alive       bool
 	Annoyer     Annoyer
+	Delta       DeltaBuffer
+	Tombstones  Tombstones
+	FieldIndex  FieldIndex
+	Quantized   Quantized
 	Runs        int32
 	DBMap       sync.Map
+	keyLocks    [keyLockShards]sync.Mutex
 }
 
 func (d *Data) GetConfig() *pb.DataConfig {
 	return d.Config
 }
 
+// getN returns the number of entries counted by the last Process
+func (dt *Data) getN() uint64 {
+	dt.RLock()
+	defer dt.RUnlock()
+	return dt.N
+}
+
+func (dt *Data) isAlive() bool {
+	dt.RLock()
+	defer dt.RUnlock()
+	return dt.Alive
+}
+
+func (dt *Data) setAlive(alive bool) {
+	dt.Lock()
+	defer dt.Unlock()
+	dt.Alive = alive
+}
+
+func (dt *Data) setDirty(dirty bool) {
+	dt.Lock()
+	defer dt.Unlock()
+	dt.Dirty = dirty
+}
+
 // NewData creates a data struct
 func NewData(config *pb.DataConfig, dataPath string) (*Data, error) {
 	dt := &Data{
@@ -64,7 +98,10 @@ func NewData(config *pb.DataConfig, dataPath string) (*Data, error) {
 	}
 	// log.Printf("Create Data\n")
 	dt.DBPath = path.Join(dataPath, config.Name)
-	dt.InitData()
+	err := dt.InitData()
+	if err != nil {
+		return nil, err
+	}
 	return dt, nil
 }
 
@@ -90,6 +127,17 @@ func (dt *Data) InitData() error {
 		// 	return err
 		// }
 		// dt.DB = db
+		if GetIndexType(dt.Config) == IndexTypeHNSW {
+			// Online index should exist before replay so that it sees every entry
+			dt.Annoyer.Index = NewIndex(dt.Config)
+		}
+		dt.FieldIndex.SetFields(dt.Config.GetIndexedFields())
+		err := dt.OpenStore()
+		if err != nil {
+			// Writes without the store would be lost, the data stays uninitialized
+			log.Printf("Data %v store error: %v\n", dt.Config.Name, err)
+			return err
+		}
 		dt.Sources = cache.New(5*time.Minute, 1*time.Minute)
 		dt.QueryCache = cache.New(5*time.Minute, 1*time.Minute)
 		dt.Alive = true
@@ -116,19 +164,23 @@ func NewTempData() (*Data, error) {
 
 // Close currently closes underlying kv store
 func (dt *Data) Close() error {
-	dt.Alive = false
+	dt.setAlive(false)
 	if dt.Sources != nil && len(dt.Sources.Items()) > 0 {
-		for dt.N > 0 {
+		for dt.getN() > 0 {
 			dt.Process(true)
 		}
 	}
-	// return dt.DB.Close()
+	if dt.Store != nil {
+		return dt.Store.Close()
+	}
 	return nil
 }
 
 // Delete currently deletes underlying data folder ignores errors.
 func (dt *Data) DeletePath() error {
-	// dt.DB.Close()
+	if dt.Store != nil {
+		dt.Store.Close()
+	}
 	os.RemoveAll(dt.DBPath)
 	return nil
 }
@@ -144,7 +196,7 @@ func (dt *Data) Run() error {
 	nextTime := getCurrentTime()
 	gcCounter := 10
 	for {
-		if !dt.Alive {
+		if !dt.isAlive() {
 			break
 		}
 		if nextTime <= getCurrentTime() {
@@ -152,10 +204,16 @@ func (dt *Data) Run() error {
 			dt.Process(false)
 			nextTime = getCurrentTime() + secondsToSleep
 			gcCounter--
-			// if gcCounter <= 0 {
-			// 	gcCounter = 10
-			// 	dt.DB.RunValueLogGC(0.5)
-			// }
+			if gcCounter <= 0 {
+				gcCounter = 10
+				err := dt.CompactStore()
+				if err != nil {
+					log.Printf("Data %v compaction error: %v\n", dt.Config.Name, err)
+				}
+			}
+		}
+		if dt.Store != nil {
+			dt.Store.Sync()
 		}
 		time.Sleep(time.Duration(1000) * time.Millisecond)
 
@@ -191,7 +249,7 @@ func (dt *Data) DataSourceDiffMap() (map[string]uint64, uint64) {
 }
 
 func CheckIfUnkownError(err error) bool {
-	if strings.Contains(err.Error(), "Number of elements is over the target") || strings.Contains(err.Error(), "Node is in drain mode") {
+	if strings.Contains(err.Error(), "Number of elements is over the target") || strings.Contains(err.Error(), "Node is in drain mode") || strings.Contains(err.Error(), ErrStaleVersion.Error()) || strings.Contains(err.Error(), ErrDeleted.Error()) {
 		return false
 	}
 	return true
@@ -379,17 +437,33 @@ func CheckIfUnkownError(err error) bool {
 // GetDataInfo out of data
 func (dt *Data) GetDataInfo() *pb.DataInfo {
 	// log.Printf("Data: %v\n", dt)
+	dt.Annoyer.RLock()
+	buildDuration := dt.Annoyer.BuildDuration
+	indexType := ""
+	trees := 0
+	if dt.Annoyer.Index != nil {
+		indexType = dt.Annoyer.Index.Type()
+		if annoyIndex, ok := dt.Annoyer.Index.(*AnnoyIndexSet); ok {
+			trees = annoyIndex.Trees
+		}
+	}
+	dt.Annoyer.RUnlock()
+	dt.RLock()
+	defer dt.RUnlock()
 	return &pb.DataInfo{
-		Avg:               dt.Avg,
-		N:                 dt.N,
-		MaxDistance:       dt.MaxDistance,
-		Hist:              dt.Hist,
-		Timestamp:         dt.Timestamp,
-		Version:           dt.Config.Version,
-		Name:              dt.Config.Name,
-		TargetN:           dt.Config.TargetN,
-		TargetUtilization: dt.Config.TargetUtilization,
-		NoTarget:          dt.Config.NoTarget,
+		Avg:                dt.Avg,
+		N:                  dt.N,
+		MaxDistance:        dt.MaxDistance,
+		Hist:               dt.Hist,
+		Timestamp:          dt.Timestamp,
+		Version:            dt.Config.Version,
+		Name:               dt.Config.Name,
+		TargetN:            dt.Config.TargetN,
+		TargetUtilization:  dt.Config.TargetUtilization,
+		NoTarget:           dt.Config.NoTarget,
+		IndexBuildDuration: uint64(buildDuration / time.Millisecond),
+		IndexTrees:         int32(trees),
+		IndexType:          indexType,
 	}
 }
 
@@ -399,7 +473,10 @@ func (dt *Data) AddSource(dataSource DataSource) error {
 		return errors.New("DataSource is nil")
 	}
 	if dt.Sources == nil {
-		dt.InitData()
+		err := dt.InitData()
+		if err != nil {
+			return err
+		}
 	}
 	if dt.Sources == nil {
 		return errors.New("Sources is still nil")
@@ -427,3 +504,20 @@ func (dt *Data) RunOnRandomSources(sourceLimit int, sourceFunction func(dataSour
 	}
 	return nil
 }
+
+// RunOnAllSources runs sourceFunction on every source in the order of their ids
+func (dt *Data) RunOnAllSources(sourceFunction func(dataSource DataSource) error) error {
+	sourceList := dt.Sources.Items()
+	ids := make([]string, 0, len(sourceList))
+	for id := range sourceList {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		err := sourceFunction(sourceList[id].Object.(DataSource))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,10 +2,15 @@ package data_test
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"log"
+	"math"
+	"math/rand"
 	"os"
+	"sort"
 	"testing"
 
 	data "github.com/bgokden/veri/data"
@@ -68,32 +73,38 @@ type NewsTitle struct {
 	Embedding []float32
 }
 
-func load_data_from_json(dt *data.Data, fname string) (*pb.Datum, error) {
-	var oneDatum *pb.Datum
+// read_data_from_json reads the news title embeddings of fname as datums
+func read_data_from_json(fname string) ([]*pb.Datum, error) {
 	f, err := os.Open(fname)
 	if err != nil {
 		return nil, err
 	}
-	index := 0
-	count := 0
+	defer f.Close()
+	datums := make([]*pb.Datum, 0)
 	s := bufio.NewScanner(f)
 	for s.Scan() {
 		var v NewsTitle
 		if err := json.Unmarshal(s.Bytes(), &v); err != nil {
 			return nil, err
 		}
-		datum := data.NewDatum(v.Embedding, uint32(len(v.Embedding)), 0, 1, 0, []byte(v.Title), []byte(v.Title), 0)
-		if oneDatum == nil && index == count {
-			oneDatum = datum
-		} else {
-			dt.Insert(datum, nil)
-		}
-		index++
+		datums = append(datums, data.NewDatum(v.Embedding, uint32(len(v.Embedding)), 0, 1, 0, []byte(v.Title), []byte(v.Title), 0))
 	}
 	if s.Err() != nil {
 		return nil, s.Err()
 	}
-	return oneDatum, nil
+	return datums, nil
+}
+
+// load_data_from_json inserts the news title embeddings of fname except the first one, which is returned as a query
+func load_data_from_json(dt *data.Data, fname string) (*pb.Datum, error) {
+	datums, err := read_data_from_json(fname)
+	if err != nil || len(datums) == 0 {
+		return nil, err
+	}
+	for _, datum := range datums[1:] {
+		dt.Insert(datum, nil)
+	}
+	return datums[0], nil
 }
 
 func TestData2(t *testing.T) {
@@ -159,61 +170,255 @@ func TestDataStreamSearch(t *testing.T) {
 	assert.Nil(t, err)
 	defer dt01.Close()
 
-	// Stream Sample doesn't work currently
-
-	// datum, err := load_data_from_json(dt01, "./testdata/news_title_embdeddings.json")
-	// assert.Nil(t, err)
-
-	// config02 := &pb.DataConfig{
-	// 	Name:    "data02",
-	// 	Version: 0,
-	// 	TargetN: 1000,
-	// }
-
-	// dt02, err := data.NewData(config02, dir)
-	// assert.Nil(t, err)
-	// defer dt02.Close()
-
-	// _, err = load_data_from_json(dt02, "./testdata/news_title_embdeddings.json")
-	// assert.Nil(t, err)
-
-	// config := data.DefaultSearchConfig()
-	// config.ScoreFuncName = "AnnoyAngularDistance"
-	// config.HigherIsBetter = true
-	// config.Limit = 10
-	// scoredDatumStream := make(chan *pb.ScoredDatum, 100)
-	// dt01.AddSource(dt02)
-	// err = dt01.AggregatedSearch(datum, scoredDatumStream, nil, config)
-	// assert.Nil(t, err)
-	// time.Sleep(1 * time.Second)
-	// close(scoredDatumStream)
-	// for e := range scoredDatumStream {
-	// 	log.Printf("label: %v score: %v\n", string(e.Datum.Value.Label), e.Score)
-	// }
-	// rand.Seed(42)
-	// datumStream := make(chan *pb.Datum, 100)
-	// err = dt01.StreamSample(datumStream, 0.5)
-	// assert.Nil(t, err)
-	// time.Sleep(1 * time.Second)
-	// close(datumStream)
-	// log.Printf("Stream Sample\n")
-	// count := 0
-	// for e := range datumStream {
-	// 	log.Printf("label %v: %v\n", count, string(e.Value.Label))
-	// 	count++
-	// }
-	// assert.Equal(t, 24, count)
-
-	// datumStreamAll := make(chan *pb.Datum, 100)
-	// err = dt01.StreamAll(datumStreamAll)
-	// assert.Nil(t, err)
-	// time.Sleep(1 * time.Second)
-	// close(datumStreamAll)
-	// log.Printf("Stream All\n")
-	// countAll := 0
-	// for e := range datumStreamAll {
-	// 	log.Printf("label %v: %v\n", countAll, string(e.Value.Label))
-	// 	countAll++
-	// }
-	// assert.Equal(t, 49, countAll)
+	_, err = load_data_from_json(dt01, "./testdata/news_title_embdeddings.json")
+	assert.Nil(t, err)
+
+	datumStream := make(chan *pb.Datum, 100)
+	err = dt01.StreamSample(datumStream, 0.5)
+	assert.Nil(t, err)
+	close(datumStream)
+	log.Printf("Stream Sample\n")
+	count := 0
+	for e := range datumStream {
+		log.Printf("label %v: %v\n", count, string(e.Value.Label))
+		count++
+	}
+	assert.True(t, count < 49)
+
+	datumStreamAll := make(chan *pb.Datum, 100)
+	err = dt01.StreamAll(datumStreamAll)
+	assert.Nil(t, err)
+	close(datumStreamAll)
+	log.Printf("Stream All\n")
+	countAll := 0
+	for e := range datumStreamAll {
+		log.Printf("label %v: %v\n", countAll, string(e.Value.Label))
+		countAll++
+	}
+	assert.Equal(t, 49, countAll)
+}
+
+type failingStreamSource struct {
+	*data.Data
+}
+
+func (f *failingStreamSource) StreamData(ctx context.Context, datumStream chan<- *pb.Datum, config *pb.StreamConfig) error {
+	return errors.New("Source failure")
+}
+
+func (f *failingStreamSource) GetID() string {
+	return "failing-stream"
+}
+
+func TestDataAggregatedStreamData(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	dt, err := data.NewData(&pb.DataConfig{Name: "stream", TargetN: 1000}, dir)
+	assert.Nil(t, err)
+	defer dt.Close()
+	peer, err := data.NewData(&pb.DataConfig{Name: "stream-peer", TargetN: 1000}, dir)
+	assert.Nil(t, err)
+	defer peer.Close()
+	for i := 0; i < 40; i++ {
+		datum := data.NewDatum([]float32{float32(i), 1}, 2, 0, 1, 0, []byte("{}"), []byte("{}"), 0)
+		if i < 20 {
+			assert.Nil(t, dt.Insert(datum, nil))
+		}
+		if i >= 10 {
+			assert.Nil(t, peer.Insert(datum, nil))
+		}
+	}
+	assert.Nil(t, dt.AddSource(peer))
+	defer dt.Sources.Flush() // Close would move the data to the sources
+
+	// Replicated datums are streamed once
+	datumStream := make(chan *pb.Datum, 100)
+	assert.Nil(t, dt.AggregatedStreamData(context.Background(), datumStream, nil))
+	close(datumStream)
+	assert.Equal(t, 40, len(datumStream))
+
+	// A cancelled export stops without a reader
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, dt.AggregatedStreamData(ctx, make(chan *pb.Datum), nil))
+
+	// Errors of sources are returned
+	assert.Nil(t, dt.AddSource(&failingStreamSource{peer}))
+	assert.NotNil(t, dt.AggregatedStreamData(context.Background(), make(chan *pb.Datum, 100), nil))
+}
+
+// newsFixture creates data of config in a temp dir with the news title embeddings
+// The first title is the query and is not inserted, the inserted datums are returned
+func newsFixture(t *testing.T, config *pb.DataConfig) (*data.Data, *pb.Datum, []*pb.Datum) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	dt, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		dt.Close()
+		os.RemoveAll(dir) // clean up
+	})
+	datums, err := read_data_from_json("./testdata/news_title_embdeddings.json")
+	assert.Nil(t, err)
+	for _, datum := range datums[1:] {
+		assert.Nil(t, dt.Insert(datum, nil))
+	}
+	return dt, datums[0], datums[1:]
+}
+
+// exactTopKeys returns the map keys of the best limit datums by brute force
+func exactTopKeys(datums []*pb.Datum, query *pb.Datum, scoreFuncName string, higherIsBetter bool, limit int) []string {
+	scoreFunc := data.GetVectorComparisonFunction(scoreFuncName)
+	sorted := append([]*pb.Datum{}, datums...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		si := scoreFunc(sorted[i].Key.Feature, query.Key.Feature)
+		sj := scoreFunc(sorted[j].Key.Feature, query.Key.Feature)
+		if higherIsBetter {
+			return si > sj
+		}
+		return si < sj
+	})
+	if len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	keys := make([]string, 0, len(sorted))
+	for _, datum := range sorted {
+		keyByte, _ := data.GetKeyAsBytes(datum)
+		keys = append(keys, data.GetMapKey(keyByte))
+	}
+	return keys
+}
+
+// resultKeys returns the map keys of a result list in order
+func resultKeys(list []*pb.ScoredDatum) []string {
+	keys := make([]string, 0, len(list))
+	for _, scoredDatum := range list {
+		keyByte, _ := data.GetKeyAsBytes(scoredDatum.Datum)
+		keys = append(keys, data.GetMapKey(keyByte))
+	}
+	return keys
+}
+
+func TestDataExactSearch(t *testing.T) {
+	dt, datum, datums := newsFixture(t, &pb.DataConfig{Name: "data3", TargetN: 1000})
+
+	for _, scoreFuncName := range []string{"VectorDistance", "QuickVectorDistance", "CosineSimilarity", "AngularDistance", "VectorMultiplication"} {
+		config := data.DefaultSearchConfig()
+		config.ScoreFuncName = scoreFuncName
+		config.HigherIsBetter = scoreFuncName != "VectorDistance" && scoreFuncName != "QuickVectorDistance"
+		config.Limit = 15
+		collector := dt.Search(datum, config)
+		assert.Equal(t, exactTopKeys(datums, datum, scoreFuncName, config.HigherIsBetter, 15), resultKeys(collector.List), scoreFuncName)
+	}
+}
+
+func TestDataIndexMetrics(t *testing.T) {
+	config4 := &pb.DataConfig{
+		Name:         "data4",
+		TargetN:      1000,
+		IndexMetrics: []string{data.AnnoyMetricAngular, data.AnnoyMetricEuclidean, "Unknown"},
+	}
+	assert.Equal(t, []string{data.AnnoyMetricAngular, data.AnnoyMetricEuclidean}, data.GetIndexMetrics(config4))
+	dt, datum, datums := newsFixture(t, config4)
+	dt.Process(true)
+
+	annoyIndex, ok := dt.Annoyer.Index.(*data.AnnoyIndexSet)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(annoyIndex.Indexes))
+	assert.Equal(t, data.AnnoyMetricEuclidean, annoyIndex.GetAnnoyIndex("AnnoyVectorDistance").Metric)
+	assert.Equal(t, data.AnnoyMetricAngular, annoyIndex.GetAnnoyIndex("AnnoyCosineSimilarity").Metric)
+	// Manhattan is not built
+	assert.Nil(t, annoyIndex.GetAnnoyIndex("AnnoyQuickVectorDistance"))
+	assert.False(t, annoyIndex.Serves("AnnoyQuickVectorDistance"))
+
+	// With full precision every item is checked, both metric indexes find the exact neighbours
+	for _, scoreFuncName := range []string{"AnnoyVectorDistance", "AnnoyCosineSimilarity"} {
+		config := data.DefaultSearchConfig()
+		config.ScoreFuncName = scoreFuncName
+		config.HigherIsBetter = scoreFuncName == "AnnoyCosineSimilarity"
+		config.Limit = 5
+		config.Precision = 1.0
+		collector := dt.SearchAnnoy(datum, config)
+		assert.Equal(t, exactTopKeys(datums, datum, scoreFuncName, config.HigherIsBetter, 5), resultKeys(collector.List), scoreFuncName)
+	}
+}
+
+func TestDataIndexMetricMismatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	// Only the angular index is built, euclidean neighbours differ by the norm
+	dt, err := data.NewData(&pb.DataConfig{Name: "mismatch", TargetN: 1000}, dir)
+	assert.Nil(t, err)
+	defer dt.Close()
+	r := rand.New(rand.NewSource(5))
+	datums := make([]*pb.Datum, 0, 500)
+	for i := 0; i < 500; i++ {
+		norm := 0.1 + 10*r.Float64()
+		angle := 2 * math.Pi * r.Float64()
+		datum := data.NewDatum([]float32{float32(norm * math.Cos(angle)), float32(norm * math.Sin(angle))}, 2, 0, 1, 0, []byte("{}"), []byte("{}"), 0)
+		assert.Nil(t, dt.Insert(datum, nil))
+		datums = append(datums, datum)
+	}
+	dt.Process(true)
+	assert.False(t, dt.Annoyer.Index.Serves("AnnoyVectorDistance"))
+
+	query := data.NewDatum([]float32{1, 0}, 2, 0, 1, 0, []byte("{}"), []byte("{}"), 0)
+	config := data.DefaultSearchConfig()
+	config.ScoreFuncName = "AnnoyVectorDistance"
+	config.HigherIsBetter = false
+	config.Limit = 10
+	collector := dt.SearchAnnoy(query, config)
+	assert.Equal(t, exactTopKeys(datums, query, "VectorDistance", false, 10), resultKeys(collector.List))
+}
+
+func TestDataDeltaSearch(t *testing.T) {
+	dt, datum, datums := newsFixture(t, &pb.DataConfig{Name: "data5", TargetN: 1000})
+	dt.Process(true)
+	assert.True(t, dt.Delta.IsEmpty())
+
+	config := data.DefaultSearchConfig()
+	config.ScoreFuncName = "AnnoyAngularDistance"
+	config.HigherIsBetter = true
+	config.Limit = 100
+	expected := exactTopKeys(datums, datum, config.ScoreFuncName, true, 100)
+	assert.Equal(t, expected, resultKeys(dt.SearchAnnoy(datum, config).List))
+
+	// Fresh insert is searchable before the next rebuild
+	err := dt.Insert(datum, nil)
+	assert.Nil(t, err)
+	assert.False(t, dt.Delta.IsEmpty())
+	withQuery := append([]*pb.Datum{datum}, datums...)
+	assert.Equal(t, exactTopKeys(withQuery, datum, config.ScoreFuncName, true, 100), resultKeys(dt.SearchAnnoy(datum, config).List))
+
+	err = dt.Delete(datum)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, resultKeys(dt.SearchAnnoy(datum, config).List))
+}
+
+func TestDataAnnoyTrees(t *testing.T) {
+	dt, datum, datums := newsFixture(t, &pb.DataConfig{Name: "data5", TargetN: 1000, AnnoyTrees: 3})
+	dt.Process(true)
+	assert.Equal(t, int32(3), dt.GetDataInfo().IndexTrees)
+
+	config := data.DefaultSearchConfig()
+	config.ScoreFuncName = "AnnoyAngularDistance"
+	config.HigherIsBetter = true
+	config.Limit = 5
+	config.Precision = 1.0
+	collector := dt.SearchAnnoy(datum, config)
+	assert.Equal(t, exactTopKeys(datums, datum, config.ScoreFuncName, true, 5), resultKeys(collector.List))
+
+	assert.Equal(t, 20, data.GetSearchK(&pb.SearchConfig{SearchK: 20, Precision: 0.5}, 5, 100))
+	assert.Equal(t, 50, data.GetSearchK(&pb.SearchConfig{Precision: 0.5}, 5, 100))
+	assert.Equal(t, -1, data.GetSearchK(&pb.SearchConfig{}, 5, 100))
 }
@@ -1,7 +1,10 @@
 package data
 
 import (
+	"hash/fnv"
+
 	"github.com/bgokden/veri/models"
+	"github.com/bgokden/veri/util"
 	pb "github.com/bgokden/veri/veriservice"
 
 	"github.com/bgokden/veri/data/gencoder"
@@ -114,6 +117,14 @@ func GetValueAsBytes(datum *pb.Datum) ([]byte, error) {
 	return gencoder.MarshalValue(datum.Value)
 }
 
+// GetMapKey derives the DBMap key from an encoded datum key
+// A 128 bit hash keeps map keys small regardless of the feature size
+func GetMapKey(keyByte []byte) string {
+	hash := fnv.New128a()
+	hash.Write(keyByte)
+	return util.EncodeToString(hash.Sum(nil))
+}
+
 func ToDatumKey(byteArray []byte) (*pb.DatumKey, error) {
 	var element pb.DatumKey
 	_, err := gencoder.UnmarshalKey(&element, byteArray)
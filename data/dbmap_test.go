@@ -0,0 +1,617 @@
+package data_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"os"
+	"sort"
+	"testing"
+
+	data "github.com/bgokden/veri/data"
+	"github.com/bgokden/veri/data/gencoder"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+func labeledDatum(i int) *pb.Datum {
+	feature := []float32{float32(i), float32(i % 7), float32(i % 3), 1}
+	label := []byte(fmt.Sprintf("label-%v", i))
+	return data.NewDatum(feature, 4, 0, 1, 0, []byte(fmt.Sprintf("group-%v", i%2)), label, 0)
+}
+
+func topLabel(collector *data.Collector) string {
+	if len(collector.List) == 0 {
+		return ""
+	}
+	return string(collector.List[0].Datum.Value.Label)
+}
+
+func TestDBMapRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	config := &pb.DataConfig{
+		Name:    "roundtrip",
+		Version: 0,
+		TargetN: 1000,
+	}
+	dt, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	for i := 0; i < 20; i++ {
+		assert.Nil(t, dt.Insert(labeledDatum(i), nil))
+	}
+	assert.Equal(t, 20, countEntries(dt))
+
+	// Entries are exact size and decode to the inserted datum
+	dt.LoopDBMap(func(entry *data.DBMapEntry) error {
+		datum, err := data.ToDatum(entry.Key, entry.Value)
+		assert.Nil(t, err)
+		i := int(datum.Key.Feature[0])
+		assert.Equal(t, labeledDatum(i).Value.Label, datum.Value.Label)
+		assert.Equal(t, labeledDatum(i).Key.GroupLabel, datum.Key.GroupLabel)
+		assert.Equal(t, int(gencoder.SizeKey(datum.Key)), len(entry.Key))
+		assert.Equal(t, int(gencoder.SizeValue(datum.Value)), len(entry.Value))
+		return nil
+	})
+
+	config5 := data.DefaultSearchConfig()
+	config5.Limit = 1
+	collector := dt.Search(labeledDatum(5), config5)
+	assert.Equal(t, "label-5", topLabel(collector))
+	assert.Equal(t, 0.0, collector.List[0].Score)
+
+	// Same key replaces the entry
+	replaced := labeledDatum(5)
+	replaced.Value.Label = []byte("label-5b")
+	assert.Nil(t, dt.Insert(replaced, nil))
+	assert.Equal(t, 20, countEntries(dt))
+	assert.Equal(t, "label-5b", topLabel(dt.Search(labeledDatum(5), config5)))
+
+	dt.Process(true)
+	annoyConfig := data.DefaultSearchConfig()
+	annoyConfig.ScoreFuncName = "AnnoyVectorDistance"
+	annoyConfig.Limit = 1
+	assert.Equal(t, "label-7", topLabel(dt.SearchAnnoy(labeledDatum(7), annoyConfig)))
+
+	assert.Nil(t, dt.Delete(labeledDatum(7)))
+	assert.Equal(t, 19, countEntries(dt))
+	assert.NotEqual(t, "label-7", topLabel(dt.Search(labeledDatum(7), config5)))
+	assert.NotEqual(t, "label-7", topLabel(dt.SearchAnnoy(labeledDatum(7), annoyConfig)))
+	dt.Close()
+
+	// Replay keeps labels and the delete
+	dt2, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	defer dt2.Close()
+	assert.Equal(t, 19, countEntries(dt2))
+	assert.Equal(t, "label-5b", topLabel(dt2.Search(labeledDatum(5), config5)))
+	assert.NotEqual(t, "label-7", topLabel(dt2.Search(labeledDatum(7), config5)))
+}
+
+func TestDBMapHNSWRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	dt, err := data.NewData(&pb.DataConfig{
+		Name:         "roundtrip-hnsw",
+		TargetN:      1000,
+		IndexType:    data.IndexTypeHNSW,
+		IndexMetrics: []string{data.AnnoyMetricEuclidean},
+	}, dir)
+	assert.Nil(t, err)
+	defer dt.Close()
+	for i := 0; i < 50; i++ {
+		assert.Nil(t, dt.Insert(labeledDatum(i), nil))
+	}
+	config := data.DefaultSearchConfig()
+	config.ScoreFuncName = "AnnoyVectorDistance"
+	config.Limit = 3
+	assert.Equal(t, "label-11", topLabel(dt.SearchAnnoy(labeledDatum(11), config)))
+	assert.Nil(t, dt.Delete(labeledDatum(11)))
+	assert.NotEqual(t, "label-11", topLabel(dt.SearchAnnoy(labeledDatum(11), config)))
+	assert.Equal(t, 49, dt.Annoyer.Index.Len())
+}
+
+func TestDBMapQuantizedRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	for _, quantization := range []string{data.QuantizationSQ, data.QuantizationPQ} {
+		dt, err := data.NewData(&pb.DataConfig{
+			Name:         "roundtrip-" + quantization,
+			TargetN:      1000,
+			Quantization: quantization,
+			PqSubspaces:  2,
+		}, dir)
+		assert.Nil(t, err)
+		random := rand.New(rand.NewSource(3))
+		datums := make([]*pb.Datum, 300)
+		for i := range datums {
+			feature := make([]float32, 8)
+			for j := range feature {
+				feature[j] = random.Float32()
+			}
+			datums[i] = data.NewDatum(feature, 8, 0, 1, 0, nil, []byte(fmt.Sprintf("label-%v", i)), 0)
+			assert.Nil(t, dt.Insert(datums[i], nil))
+		}
+		dt.Process(true)
+		assert.NotNil(t, dt.Quantized.Quantizer)
+		assert.Equal(t, 300, countCodes(dt, false))
+
+		config := data.DefaultSearchConfig()
+		config.Quantized = true
+		config.Limit = 5
+		collector := dt.Search(datums[42], config)
+		assert.Equal(t, 5, len(collector.List))
+		assert.Equal(t, "label-42", topLabel(collector))
+		assert.Equal(t, 0.0, collector.List[0].Score)
+
+		// Pages continue by exact scores, every entry is a candidate
+		config.Limit = 15
+		config.RerankFactor = 60
+		labels := make(map[string]bool)
+		for _, scoredDatum := range dt.Search(datums[7], config).List {
+			labels[string(scoredDatum.Datum.Value.Label)] = true
+		}
+		config.Limit = 5
+		for page := 0; page < 3; page++ {
+			collector = dt.Search(datums[7], config)
+			assert.Equal(t, 5, len(collector.List))
+			for _, scoredDatum := range collector.List {
+				assert.True(t, labels[string(scoredDatum.Datum.Value.Label)])
+				delete(labels, string(scoredDatum.Datum.Value.Label))
+			}
+			config.PageToken = data.NextCursor(collector.List, nil).Token()
+		}
+		assert.Equal(t, 0, len(labels))
+		config.PageToken = ""
+
+		assert.Nil(t, dt.Delete(datums[42]))
+		assert.Equal(t, 299, countCodes(dt, false))
+		assert.NotEqual(t, "label-42", topLabel(dt.Search(datums[42], config)))
+		dt.Close()
+	}
+}
+
+func TestDBMapQuantizedExactSearch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	// Two centroids can not tell the nearest datums apart
+	dt, err := data.NewData(&pb.DataConfig{
+		Name:         "quantized-exact",
+		TargetN:      1000,
+		Quantization: data.QuantizationPQ,
+		PqSubspaces:  1,
+		PqCentroids:  2,
+	}, dir)
+	assert.Nil(t, err)
+	defer dt.Close()
+	random := rand.New(rand.NewSource(11))
+	datums := make([]*pb.Datum, 300)
+	for i := range datums {
+		feature := make([]float32, 8)
+		for j := range feature {
+			feature[j] = random.Float32()
+		}
+		datums[i] = data.NewDatum(feature, 8, 0, 1, 0, nil, []byte(fmt.Sprintf("label-%v", i)), 0)
+		assert.Nil(t, dt.Insert(datums[i], nil))
+	}
+	dt.Process(true)
+	assert.NotNil(t, dt.Quantized.Quantizer)
+
+	// Exact search scores every feature unless quantized search is asked for
+	query := datums[7]
+	sorted := make([]*pb.Datum, len(datums))
+	copy(sorted, datums)
+	sort.Slice(sorted, func(i, j int) bool {
+		return data.VectorDistance(query.Key.Feature, sorted[i].Key.Feature) < data.VectorDistance(query.Key.Feature, sorted[j].Key.Feature)
+	})
+	config := data.DefaultSearchConfig()
+	config.Limit = 10
+	config.RerankFactor = 1
+	collector := dt.Search(query, config)
+	assert.Equal(t, 10, len(collector.List))
+	for i, scoredDatum := range collector.List {
+		assert.Equal(t, string(sorted[i].Value.Label), string(scoredDatum.Datum.Value.Label))
+	}
+}
+
+// countCodes counts entries with a code, or only codes only entries
+func countCodes(dt *data.Data, codesOnly bool) int {
+	count := 0
+	dt.LoopDBMap(func(entry *data.DBMapEntry) error {
+		if entry.Code != nil && (!codesOnly || entry.CodesOnly()) {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+func TestDBMapCodesOnly(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	config := &pb.DataConfig{
+		Name:         "codes-only",
+		TargetN:      1000,
+		Quantization: data.QuantizationSQ,
+		CodesOnly:    true,
+	}
+	dt, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	random := rand.New(rand.NewSource(5))
+	datums := make([]*pb.Datum, 300)
+	for i := range datums {
+		feature := make([]float32, 8)
+		for j := range feature {
+			feature[j] = random.Float32()
+		}
+		datums[i] = data.NewDatum(feature, 8, 0, 1, 0, nil, []byte(fmt.Sprintf("label-%v", i)), 0)
+	}
+	for _, datum := range datums[:200] {
+		assert.Nil(t, dt.Insert(datum, nil))
+	}
+	dt.Process(true)
+	assert.Equal(t, 200, countCodes(dt, true))
+	for _, datum := range datums[200:] {
+		assert.Nil(t, dt.Insert(datum, nil))
+	}
+	assert.Equal(t, 300, countCodes(dt, true))
+
+	searchConfig := data.DefaultSearchConfig()
+	searchConfig.Limit = 5
+	for _, i := range []int{42, 242} {
+		collector := dt.Search(datums[i], searchConfig)
+		assert.Equal(t, fmt.Sprintf("label-%v", i), topLabel(collector))
+		// Features are decoded, they are close to the inserted features
+		assert.Equal(t, 8, len(collector.List[0].Datum.Key.Feature))
+		assert.InDelta(t, 0.0, collector.List[0].Score, 0.01)
+	}
+	datum, _, found := dt.Get(datums[7].Key)
+	assert.True(t, found)
+	assert.Equal(t, "label-7", string(datum.Value.Label))
+	assert.Nil(t, dt.Delete(datums[7]))
+	_, _, found = dt.Get(datums[7].Key)
+	assert.False(t, found)
+	dt.Close()
+
+	// The log has the quantizer and coded records
+	dt2, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 299, countCodes(dt2, true))
+	assert.Nil(t, dt2.CompactStore())
+	dt2.Close()
+
+	dt3, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	defer dt3.Close()
+	assert.Equal(t, 299, countCodes(dt3, true))
+	_, _, found = dt3.Get(datums[7].Key)
+	assert.False(t, found)
+	datum, _, found = dt3.Get(datums[242].Key)
+	assert.True(t, found)
+	assert.Equal(t, "label-242", string(datum.Value.Label))
+	assert.Equal(t, "label-42", topLabel(dt3.Search(datums[42], searchConfig)))
+
+	// Codes are not encoded again when the data grows
+	quantizer := dt3.Quantized.Quantizer
+	for i := 0; i < 200; i++ {
+		feature := make([]float32, 8)
+		for j := range feature {
+			feature[j] = random.Float32()
+		}
+		assert.Nil(t, dt3.Insert(data.NewDatum(feature, 8, 0, 1, 0, nil, []byte("more"), 0), nil))
+	}
+	dt3.Process(true)
+	assert.True(t, quantizer == dt3.Quantized.Quantizer)
+	assert.Equal(t, 499, countCodes(dt3, true))
+	assert.Equal(t, "label-42", topLabel(dt3.Search(datums[42], searchConfig)))
+}
+
+func TestDBMapUpsertVersion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	config := &pb.DataConfig{
+		Name:    "versioned",
+		Version: 0,
+		TargetN: 1000,
+	}
+	dt, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	versioned := func(label string, version uint64) *pb.Datum {
+		datum := labeledDatum(3)
+		datum.Value.Label = []byte(label)
+		datum.Value.Version = version
+		return datum
+	}
+	searchConfig := data.DefaultSearchConfig()
+	searchConfig.Limit = 1
+
+	applied, err := dt.Upsert(versioned("v2", 2), nil)
+	assert.Nil(t, err)
+	assert.True(t, applied)
+
+	// Older version is rejected, Insert drops it silently
+	applied, err = dt.Upsert(versioned("v1", 1), nil)
+	assert.Nil(t, err)
+	assert.False(t, applied)
+	assert.Nil(t, dt.Insert(versioned("v1", 1), nil))
+	assert.Equal(t, "v2", topLabel(dt.Search(labeledDatum(3), searchConfig)))
+
+	// Same or newer version wins
+	applied, err = dt.Upsert(versioned("v2b", 2), nil)
+	assert.Nil(t, err)
+	assert.True(t, applied)
+	applied, err = dt.Upsert(versioned("v3", 3), nil)
+	assert.Nil(t, err)
+	assert.True(t, applied)
+	assert.Equal(t, 1, countEntries(dt))
+	dt.Close()
+
+	// Stale writes are not logged
+	dt2, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	defer dt2.Close()
+	assert.Equal(t, "v3", topLabel(dt2.Search(labeledDatum(3), searchConfig)))
+
+	// Applied writes report replication errors with the applied flag
+	dt2.Config.EnforceReplicationOnInsert = true
+	dt2.Config.ReplicationOnInsert = 2
+	applied, err = dt2.Upsert(versioned("v4", 4), nil)
+	assert.Equal(t, data.ErrUnderReplicated, err)
+	assert.True(t, applied)
+	assert.Equal(t, "v4", topLabel(dt2.Search(labeledDatum(3), searchConfig)))
+}
+
+func TestDBMapDeleteTombstone(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	config := &pb.DataConfig{
+		Name:    "tombstoned",
+		Version: 0,
+		TargetN: 1000,
+	}
+	dt, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	for i := 0; i < 10; i++ {
+		datum := labeledDatum(i)
+		if i%2 == 0 {
+			datum.Value.Label = []byte(fmt.Sprintf(`{"even": true, "i": %v}`, i))
+		}
+		assert.Nil(t, dt.Insert(datum, nil))
+	}
+
+	deleted, err := dt.Remove(&pb.DeleteRequest{Datum: []*pb.Datum{labeledDatum(1)}})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), deleted)
+	deleted, err = dt.Remove(&pb.DeleteRequest{Filters: []string{"even"}})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), deleted)
+	assert.Equal(t, 4, countEntries(dt))
+	_, err = dt.Remove(&pb.DeleteRequest{})
+	assert.NotNil(t, err)
+
+	// A copy from a peer does not resurrect the datum, a newer version does
+	applied, err := dt.Upsert(labeledDatum(1), nil)
+	assert.Equal(t, data.ErrDeleted, err)
+	assert.False(t, applied)
+	newer := labeledDatum(2)
+	newer.Value.Version = 1
+	applied, err = dt.Upsert(newer, nil)
+	assert.Nil(t, err)
+	assert.True(t, applied)
+	assert.Equal(t, 5, countEntries(dt))
+	// An unversioned delete keeps only the deleted value out
+	relabeled := labeledDatum(1)
+	relabeled.Value.Label = []byte("label-1-new")
+	applied, err = dt.Upsert(relabeled, nil)
+	assert.Nil(t, err)
+	assert.True(t, applied)
+	assert.Equal(t, 6, countEntries(dt))
+	assert.Nil(t, dt.CompactStore())
+	dt.Close()
+
+	// Tombstones survive restart and compaction
+	dt2, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	defer dt2.Close()
+	assert.Equal(t, 6, countEntries(dt2))
+	evenDatum := func(i int) *pb.Datum {
+		datum := labeledDatum(i)
+		datum.Value.Label = []byte(fmt.Sprintf(`{"even": true, "i": %v}`, i))
+		return datum
+	}
+	assert.Equal(t, data.ErrDeleted, dt2.Insert(evenDatum(4), nil))
+	assert.Nil(t, dt2.Insert(labeledDatum(4), nil))
+	assert.Nil(t, dt2.Delete(labeledDatum(4)))
+	assert.Equal(t, 6, countEntries(dt2))
+	statusList, err := dt2.InsertBatch([]*pb.InsertDatumWithConfig{{Datum: evenDatum(6)}})
+	assert.Nil(t, err)
+	assert.False(t, statusList[0].Applied)
+	assert.Equal(t, data.ErrDeleted.Error(), statusList[0].Error)
+}
+
+func TestDBMapDeletePropagation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	// Sources form a ring, a delete reaches sources of sources and stops
+	ring := make([]*data.Data, 3)
+	for i := range ring {
+		ring[i], err = data.NewData(&pb.DataConfig{Name: fmt.Sprintf("ring-%v", i), TargetN: 1000}, dir)
+		assert.Nil(t, err)
+		defer ring[i].Close()
+		for j := 0; j < 10; j++ {
+			datum := labeledDatum(j)
+			if j%2 == 0 {
+				datum.Value.Label = []byte(fmt.Sprintf(`{"even": true, "i": %v}`, j))
+			}
+			assert.Nil(t, ring[i].Insert(datum, nil))
+		}
+	}
+	for i := range ring {
+		assert.Nil(t, ring[i].AddSource(ring[(i+1)%len(ring)]))
+		defer ring[i].Sources.Flush() // Close would move the data to closed sources
+	}
+	deleted, err := ring[0].Remove(&pb.DeleteRequest{Datum: []*pb.Datum{labeledDatum(1)}})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), deleted)
+	deleted, err = ring[0].Remove(&pb.DeleteRequest{Filters: []string{"even"}})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), deleted)
+	for i := range ring {
+		assert.Equal(t, 4, countEntries(ring[i]))
+	}
+}
+
+func TestDBMapInsertBatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	config := &pb.DataConfig{
+		Name:    "batched",
+		Version: 0,
+		TargetN: 1000,
+	}
+	dt, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	newer := labeledDatum(0)
+	newer.Value.Version = 2
+	assert.Nil(t, dt.Insert(newer, nil))
+
+	datumList := make([]*pb.InsertDatumWithConfig, 0, 100)
+	for i := 0; i < 100; i++ {
+		datumList = append(datumList, &pb.InsertDatumWithConfig{Datum: labeledDatum(i)})
+	}
+	statusList, err := dt.InsertBatch(datumList)
+	assert.Nil(t, err)
+	assert.Equal(t, 100, len(statusList))
+	assert.False(t, statusList[0].Applied)
+	assert.Equal(t, "", statusList[0].Error)
+	for _, status := range statusList[1:] {
+		assert.True(t, status.Applied)
+	}
+	assert.Equal(t, 100, countEntries(dt))
+	dt.Close()
+
+	dt2, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	defer dt2.Close()
+	assert.Equal(t, 100, countEntries(dt2))
+	searchConfig := data.DefaultSearchConfig()
+	searchConfig.Limit = 1
+	assert.Equal(t, "label-42", topLabel(dt2.Search(labeledDatum(42), searchConfig)))
+}
+
+func TestDBMapInsertBatchRejected(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	dt, err := data.NewData(&pb.DataConfig{Name: "limited", TargetN: 10}, dir)
+	assert.Nil(t, err)
+	defer dt.DeletePath() // Close would move data to the source
+
+	datumList := []*pb.InsertDatumWithConfig{{}, {Datum: &pb.Datum{}}}
+	for i := 0; i < 15; i++ {
+		datumList = append(datumList, &pb.InsertDatumWithConfig{Datum: labeledDatum(i)})
+	}
+	statusList, err := dt.InsertBatch(datumList)
+	assert.Nil(t, err)
+	assert.Equal(t, 17, len(statusList))
+	assert.Equal(t, data.ErrMissingDatum.Error(), statusList[0].Error)
+	assert.Equal(t, data.ErrMissingDatum.Error(), statusList[1].Error)
+	for _, status := range statusList[2:10] {
+		assert.True(t, status.Applied)
+	}
+	for _, status := range statusList[10:] {
+		assert.False(t, status.Applied)
+		assert.Equal(t, data.ErrOverTarget.Error(), status.Error)
+	}
+	assert.Equal(t, 8, countEntries(dt))
+	assert.Equal(t, data.ErrMissingDatum, dt.Insert(nil, nil))
+}
+
+func TestDBMapGet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	dt, err := data.NewData(&pb.DataConfig{Name: "local", TargetN: 1000}, dir)
+	assert.Nil(t, err)
+	defer dt.DeletePath() // Close would move data to the source
+	peer, err := data.NewData(&pb.DataConfig{Name: "peer", TargetN: 1000}, dir)
+	assert.Nil(t, err)
+	defer peer.Close()
+	assert.Nil(t, dt.Insert(labeledDatum(1), &pb.InsertConfig{TTL: 100}))
+	assert.Nil(t, peer.Insert(labeledDatum(2), nil))
+
+	datum, exprireAt, found := dt.Get(labeledDatum(1).Key)
+	assert.True(t, found)
+	assert.Equal(t, "label-1", string(datum.Value.Label))
+	assert.True(t, exprireAt > 0)
+	_, _, found = dt.Get(labeledDatum(2).Key)
+	assert.False(t, found)
+
+	// Misses are looked up in sources
+	keyList := []*pb.DatumKey{labeledDatum(1).Key, labeledDatum(2).Key, labeledDatum(3).Key}
+	result, err := dt.MultiGet(keyList)
+	assert.Nil(t, err)
+	assert.True(t, result[0].Found)
+	assert.False(t, result[1].Found)
+	assert.Nil(t, dt.AddSource(peer))
+	result, err = dt.AggregatedMultiGet(keyList)
+	assert.Nil(t, err)
+	assert.True(t, result[0].Found)
+	assert.True(t, result[0].TTL > 90)
+	assert.True(t, result[1].Found)
+	assert.Equal(t, "label-2", string(result[1].Datum.Value.Label))
+	assert.Equal(t, int64(0), result[1].ExpireAt)
+	assert.False(t, result[2].Found)
+
+	// Missing keys are not found
+	_, _, found = dt.Get(nil)
+	assert.False(t, found)
+	result, err = dt.AggregatedMultiGet([]*pb.DatumKey{nil, labeledDatum(2).Key})
+	assert.Nil(t, err)
+	assert.False(t, result[0].Found)
+	assert.True(t, result[1].Found)
+}
@@ -1,12 +1,20 @@
 package data
 
 import (
+	"time"
+
 	pb "github.com/bgokden/veri/veriservice"
 )
 
 // Delete delete data to internal kv store
+// The key keeps a tombstone for DefaultTombstoneTTL, the delete is not sent to sources
 func (dt *Data) Delete(datum *pb.Datum) error {
-	return dt.DeleteBDMap(datum)
+	keyByte, err := GetKeyAsBytes(datum)
+	if err != nil {
+		return err
+	}
+	_, _, err = dt.deleteWithTombstone(GetMapKey(keyByte), datum.GetValue(), time.Now().Unix()+DefaultTombstoneTTL)
+	return err
 	// keyByte, err := GetKeyAsBytes(datum)
 	// if err != nil {
 	// 	return err
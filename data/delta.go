@@ -0,0 +1,117 @@
+package data
+
+import (
+	"sync"
+	"time"
+)
+
+// DeltaEntry is an entry inserted after the last index build started
+type DeltaEntry struct {
+	Entry    *DBMapEntry
+	Sequence uint64
+}
+
+// DeltaBuffer keeps changes that are not in the annoy index yet
+// Inserts are scanned exactly and deletes mask index results until the next rebuild
+type DeltaBuffer struct {
+	sync.RWMutex
+	Sequence   uint64
+	Entries    map[string]*DeltaEntry
+	Tombstones map[string]uint64
+}
+
+func (db *DeltaBuffer) init() {
+	if db.Entries == nil {
+		db.Entries = make(map[string]*DeltaEntry)
+		db.Tombstones = make(map[string]uint64)
+	}
+}
+
+// Insert adds an entry to the buffer, an earlier delete of the same key is overridden
+func (db *DeltaBuffer) Insert(key string, entry *DBMapEntry) {
+	db.Lock()
+	defer db.Unlock()
+	db.init()
+	db.Sequence++
+	delete(db.Tombstones, key)
+	db.Entries[key] = &DeltaEntry{
+		Entry:    entry,
+		Sequence: db.Sequence,
+	}
+}
+
+// Delete removes an entry from the buffer and masks it in the index
+func (db *DeltaBuffer) Delete(key string) {
+	db.Lock()
+	defer db.Unlock()
+	db.init()
+	db.Sequence++
+	delete(db.Entries, key)
+	db.Tombstones[key] = db.Sequence
+}
+
+// Mark returns a sequence, changes before it are visible to an index build starting now
+func (db *DeltaBuffer) Mark() uint64 {
+	db.Lock()
+	defer db.Unlock()
+	db.Sequence++
+	return db.Sequence
+}
+
+// Trim drops changes before sequence since they are in the new index
+func (db *DeltaBuffer) Trim(sequence uint64) {
+	db.Lock()
+	defer db.Unlock()
+	for key, deltaEntry := range db.Entries {
+		if deltaEntry.Sequence < sequence {
+			delete(db.Entries, key)
+		}
+	}
+	for key, tombstoneSequence := range db.Tombstones {
+		if tombstoneSequence < sequence {
+			delete(db.Tombstones, key)
+		}
+	}
+}
+
+// IsEmpty is true if there is no change since the last index build
+func (db *DeltaBuffer) IsEmpty() bool {
+	db.RLock()
+	defer db.RUnlock()
+	return len(db.Entries) == 0 && len(db.Tombstones) == 0
+}
+
+// Len is the number of changes since the last index build
+func (db *DeltaBuffer) Len() int {
+	db.RLock()
+	defer db.RUnlock()
+	return len(db.Entries) + len(db.Tombstones)
+}
+
+// IsMasked is true if an index result with key is deleted or replaced by a newer insert
+func (db *DeltaBuffer) IsMasked(key string) bool {
+	db.RLock()
+	defer db.RUnlock()
+	if _, ok := db.Tombstones[key]; ok {
+		return true
+	}
+	_, ok := db.Entries[key]
+	return ok
+}
+
+// LoopEntries runs entryFunction on every entry that is not expired
+func (db *DeltaBuffer) LoopEntries(entryFunction func(entry *DBMapEntry) error) error {
+	db.RLock()
+	defer db.RUnlock()
+	now := time.Now().Unix()
+	for _, deltaEntry := range db.Entries {
+		if deltaEntry.Entry.ExprireAt != 0 && deltaEntry.Entry.ExprireAt <= now {
+			continue
+		}
+		err := entryFunction(deltaEntry.Entry)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package data
+
+import (
+	"github.com/bgokden/veri/util"
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+// IsDiversified is true if results of config are re-ranked for diversity
+// A diversified page is not a prefix of the score order, so a cursor can not continue it
+func IsDiversified(config *pb.SearchConfig) bool {
+	lambda := config.GetMmrLambda()
+	return (lambda > 0 && lambda < 1) || config.GetMaxPerGroup() > 0
+}
+
+// Diversify re-ranks a sorted result list for diversity as configured in config
+// MMRLambda between 0 and 1 enables maximal marginal relevance, lower values prefer diverse datums
+// MaxPerGroup caps the number of datums with the same group label
+// The list is returned as is if diversification is not configured
+func Diversify(list []*pb.ScoredDatum, config *pb.SearchConfig) []*pb.ScoredDatum {
+	lambda := config.GetMmrLambda()
+	if lambda > 0 && lambda < 1 {
+		list = maximalMarginalRelevance(list, lambda)
+	}
+	if maxPerGroup := config.GetMaxPerGroup(); maxPerGroup > 0 {
+		list = capPerGroup(list, maxPerGroup)
+	}
+	return list
+}
+
+// maximalMarginalRelevance greedily picks the datum maximizing
+// lambda * relevance - (1 - lambda) * maximum similarity to the picked datums
+// Relevance is the score scaled to [0, 1] from the last to the first datum of the sorted list
+// Similarity is the cosine similarity of features
+func maximalMarginalRelevance(list []*pb.ScoredDatum, lambda float64) []*pb.ScoredDatum {
+	if len(list) <= 2 {
+		return list
+	}
+	best, worst := list[0].Score, list[len(list)-1].Score
+	relevance := make([]float64, len(list))
+	for i, scoredDatum := range list {
+		relevance[i] = 1
+		if best != worst {
+			relevance[i] = (scoredDatum.Score - worst) / (best - worst)
+		}
+	}
+	picked := make([]*pb.ScoredDatum, 0, len(list))
+	used := make([]bool, len(list))
+	maxSimilarity := make([]float64, len(list))
+	for len(picked) < len(list) {
+		pick := -1
+		pickValue := 0.0
+		for i := range list {
+			if used[i] {
+				continue
+			}
+			value := lambda*relevance[i] - (1-lambda)*maxSimilarity[i]
+			if pick < 0 || value > pickValue {
+				pick = i
+				pickValue = value
+			}
+		}
+		used[pick] = true
+		picked = append(picked, list[pick])
+		for i, scoredDatum := range list {
+			if used[i] {
+				continue
+			}
+			similarity := CosineSimilarity(scoredDatum.Datum.Key.Feature, list[pick].Datum.Key.Feature)
+			if similarity > maxSimilarity[i] {
+				maxSimilarity[i] = similarity
+			}
+		}
+	}
+	return picked
+}
+
+// capPerGroup keeps at most maxPerGroup datums of every group label in list order
+func capPerGroup(list []*pb.ScoredDatum, maxPerGroup uint32) []*pb.ScoredDatum {
+	counts := make(map[string]uint32)
+	capped := make([]*pb.ScoredDatum, 0, len(list))
+	for _, scoredDatum := range list {
+		group := util.EncodeToString(scoredDatum.GetDatum().GetKey().GetGroupLabel())
+		if counts[group] >= maxPerGroup {
+			continue
+		}
+		counts[group]++
+		capped = append(capped, scoredDatum)
+	}
+	return capped
+}
@@ -0,0 +1,93 @@
+package data_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+
+	data "github.com/bgokden/veri/data"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+func TestDiversify(t *testing.T) {
+	scoredDatumList := []*pb.ScoredDatum{
+		{Datum: data.NewDatum([]float32{1, 0}, 2, 0, 1, 0, []byte(`"x"`), []byte(`"a"`), 0), Score: 1},
+		{Datum: data.NewDatum([]float32{0.99, 0.01}, 2, 0, 1, 0, []byte(`"x"`), []byte(`"b"`), 0), Score: 0.99},
+		{Datum: data.NewDatum([]float32{0.7, 0.7}, 2, 0, 1, 0, []byte(`"y"`), []byte(`"c"`), 0), Score: 0.9},
+		{Datum: data.NewDatum([]float32{0, 1}, 2, 0, 1, 0, []byte(`"y"`), []byte(`"d"`), 0), Score: 0},
+	}
+	labels := func(aggregator data.AggregatorInterface) []string {
+		for _, scoredDatum := range scoredDatumList {
+			aggregator.Insert(scoredDatum)
+		}
+		result := make([]string, 0)
+		for _, scoredDatum := range aggregator.Result() {
+			result = append(result, string(scoredDatum.Datum.Value.Label))
+		}
+		return result
+	}
+	config := data.DefaultSearchConfig()
+	config.HigherIsBetter = true
+	config.ResultLimit = 2
+	assert.Equal(t, []string{`"a"`, `"b"`}, labels(data.NewRootAggrator(config, false, nil)))
+
+	config.MmrLambda = 0.5
+	assert.Equal(t, []string{`"a"`, `"c"`}, labels(data.NewRootAggrator(config, false, nil)))
+	// Intermediate merges keep every candidate for the root
+	assert.Equal(t, []string{`"a"`, `"b"`, `"c"`, `"d"`}, labels(data.NewAggrator(config, false, nil)))
+
+	config.MmrLambda = 0
+	config.ResultLimit = 0
+	config.MaxPerGroup = 1
+	assert.Equal(t, []string{`"a"`, `"c"`}, labels(data.NewRootAggrator(config, false, nil)))
+}
+
+func TestDataDiversifiedSearch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	dt, err := data.NewData(&pb.DataConfig{Name: "diverse", TargetN: 1000}, dir)
+	assert.Nil(t, err)
+	defer dt.Close()
+	peer, err := data.NewData(&pb.DataConfig{Name: "diverse-peer", TargetN: 1000}, dir)
+	assert.Nil(t, err)
+	defer peer.Close()
+	for i := 0; i < 10; i++ {
+		// the five nearest datums share a group
+		group := []byte(`"near"`)
+		target := dt
+		if i >= 5 {
+			group = []byte(fmt.Sprintf(`"far%v"`, i))
+			target = peer
+		}
+		assert.Nil(t, target.Insert(data.NewDatum([]float32{float32(i), 1}, 2, 0, 1, 0, group, []byte("{}"), 0), nil))
+	}
+	recording := &recordingSource{Data: peer}
+	assert.Nil(t, dt.AddSource(recording))
+	defer dt.Sources.Flush() // Close would move the data to the sources
+
+	query := data.NewDatum([]float32{0, 1}, 2, 0, 1, 0, nil, nil, 0)
+	config := data.DefaultSearchConfig()
+	config.Limit = 10
+	config.ResultLimit = 3
+	config.MaxPerGroup = 1
+	result, _, err := dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+	assert.Nil(t, err)
+	groups := make([]string, 0, len(result))
+	for _, scoredDatum := range result {
+		groups = append(groups, string(scoredDatum.Datum.Key.GroupLabel))
+	}
+	assert.Equal(t, []string{`"near"`, `"far5"`, `"far6"`}, groups)
+	// Sources return every candidate for the merge of the caller
+	assert.Equal(t, 1, len(recording.configs))
+	assert.Equal(t, uint32(0), recording.configs[0].MaxPerGroup)
+	assert.Equal(t, uint64(0), recording.configs[0].ResultLimit)
+	assert.Equal(t, uint32(1), config.MaxPerGroup)
+}
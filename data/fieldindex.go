@@ -0,0 +1,345 @@
+package data
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/bgokden/veri/veriservice"
+	"github.com/tidwall/gjson"
+)
+
+// ErrFieldNotIndexed is returned when a field is not in IndexedFields of DataConfig
+var ErrFieldNotIndexed = errors.New("Field is not indexed")
+
+// FieldIndex is an inverted index from values of declared label and group label fields to map keys
+// Fields are declared as in filters, e.g. label.category or group.brand
+type FieldIndex struct {
+	sync.RWMutex
+	Fields    []string
+	Values    map[string]map[string]map[string]struct{} // field -> value -> map keys
+	KeyValues map[string][][2]string                    // map key -> field and value pairs
+}
+
+// SetFields declares the indexed fields, unknown prefixes are ignored
+func (fi *FieldIndex) SetFields(fields []string) {
+	fi.Lock()
+	defer fi.Unlock()
+	fi.Fields = make([]string, 0, len(fields))
+	fi.Values = make(map[string]map[string]map[string]struct{})
+	fi.KeyValues = make(map[string][][2]string)
+	for _, field := range fields {
+		if !strings.HasPrefix(field, "label.") && !strings.HasPrefix(field, "group.") {
+			log.Printf("Indexed field %v should start with label. or group.\n", field)
+			continue
+		}
+		fi.Fields = append(fi.Fields, field)
+		fi.Values[field] = make(map[string]map[string]struct{})
+	}
+}
+
+// IsIndexed is true if field is declared
+func (fi *FieldIndex) IsIndexed(field string) bool {
+	fi.RLock()
+	defer fi.RUnlock()
+	_, ok := fi.Values[field]
+	return ok
+}
+
+// fieldValueKey is the index key of a JSON value, arrays are indexed by their elements
+// Keys are tagged by the type of the value, s for strings, n for numbers and r for other raw JSON
+func fieldValueKey(result gjson.Result) string {
+	switch result.Type {
+	case gjson.String:
+		return "s" + result.Str
+	case gjson.Number:
+		return "n" + strconv.FormatFloat(result.Num, 'g', -1, 64)
+	}
+	return "r" + result.Raw
+}
+
+// fieldValueOfKey converts an index key back to JSON
+func fieldValueOfKey(valueKey string) string {
+	switch {
+	case strings.HasPrefix(valueKey, "s"):
+		return strconv.Quote(valueKey[1:])
+	case strings.HasPrefix(valueKey, "n"), strings.HasPrefix(valueKey, "r"):
+		return valueKey[1:]
+	}
+	return valueKey
+}
+
+// ParseFieldValue reads a JSON value, a text that is not JSON is a string
+func ParseFieldValue(value string) gjson.Result {
+	if gjson.Valid(value) {
+		return gjson.Parse(value)
+	}
+	return gjson.Result{Type: gjson.String, Str: value}
+}
+
+func fieldValueKeys(datum *pb.Datum, field string) []string {
+	json := datum.GetValue().GetLabel()
+	path := strings.TrimPrefix(field, "label.")
+	if strings.HasPrefix(field, "group.") {
+		json = datum.GetKey().GetGroupLabel()
+		path = strings.TrimPrefix(field, "group.")
+	}
+	result := gjson.GetBytes(json, path)
+	if !result.Exists() {
+		return nil
+	}
+	if result.IsArray() {
+		valueKeys := make([]string, 0)
+		result.ForEach(func(_, element gjson.Result) bool {
+			valueKeys = append(valueKeys, fieldValueKey(element))
+			return true
+		})
+		return valueKeys
+	}
+	return []string{fieldValueKey(result)}
+}
+
+// Insert indexes the fields of datum under key, older values of key are replaced
+func (fi *FieldIndex) Insert(key string, datum *pb.Datum) {
+	fi.Lock()
+	defer fi.Unlock()
+	if len(fi.Fields) == 0 {
+		return
+	}
+	fi.delete(key)
+	pairs := make([][2]string, 0, len(fi.Fields))
+	for _, field := range fi.Fields {
+		for _, valueKey := range fieldValueKeys(datum, field) {
+			keys, ok := fi.Values[field][valueKey]
+			if !ok {
+				keys = make(map[string]struct{})
+				fi.Values[field][valueKey] = keys
+			}
+			keys[key] = struct{}{}
+			pairs = append(pairs, [2]string{field, valueKey})
+		}
+	}
+	if len(pairs) > 0 {
+		fi.KeyValues[key] = pairs
+	}
+}
+
+// Delete removes key from the index
+func (fi *FieldIndex) Delete(key string) {
+	fi.Lock()
+	defer fi.Unlock()
+	fi.delete(key)
+}
+
+func (fi *FieldIndex) delete(key string) {
+	for _, pair := range fi.KeyValues[key] {
+		keys := fi.Values[pair[0]][pair[1]]
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(fi.Values[pair[0]], pair[1])
+		}
+	}
+	delete(fi.KeyValues, key)
+}
+
+// Keys returns map keys of datums with one of the values in field
+// It returns false if the field is not indexed
+func (fi *FieldIndex) Keys(field string, values []gjson.Result) (map[string]struct{}, bool) {
+	fi.RLock()
+	defer fi.RUnlock()
+	valueMap, ok := fi.Values[field]
+	if !ok {
+		return nil, false
+	}
+	result := make(map[string]struct{})
+	for _, value := range values {
+		for key := range valueMap[fieldValueKey(value)] {
+			result[key] = struct{}{}
+		}
+	}
+	return result, true
+}
+
+// Counts returns the number of datums for every value of field, most frequent first
+// Only keys for which live is true are counted, values without such keys are skipped
+func (fi *FieldIndex) Counts(field string, live func(key string) bool) ([]*pb.FieldValueCount, bool) {
+	fi.RLock()
+	defer fi.RUnlock()
+	valueMap, ok := fi.Values[field]
+	if !ok {
+		return nil, false
+	}
+	counts := make([]*pb.FieldValueCount, 0, len(valueMap))
+	for valueKey, keys := range valueMap {
+		count := uint64(0)
+		for key := range keys {
+			if live(key) {
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		counts = append(counts, &pb.FieldValueCount{
+			Value: fieldValueOfKey(valueKey),
+			Count: count,
+		})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count == counts[j].Count {
+			return counts[i].Value < counts[j].Value
+		}
+		return counts[i].Count > counts[j].Count
+	})
+	return counts, true
+}
+
+// liveEntry returns the entry of key if it is not expired at now
+func (dt *Data) liveEntry(key string, now int64) (*DBMapEntry, bool) {
+	if value, ok := dt.DBMap.Load(key); ok {
+		if entry, ok := value.(*DBMapEntry); ok && (entry.ExprireAt == 0 || entry.ExprireAt > now) {
+			return entry, true
+		}
+	}
+	return nil, false
+}
+
+// loadEntries returns entries of keys which are not expired
+func (dt *Data) loadEntries(keys map[string]struct{}) []*DBMapEntry {
+	entries := make([]*DBMapEntry, 0, len(keys))
+	now := time.Now().Unix()
+	for key := range keys {
+		if entry, ok := dt.liveEntry(key, now); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// ListByField returns up to limit datums with value in field and the number of such datums
+// Limit 0 only counts
+func (dt *Data) ListByField(field string, value string, limit int) ([]*pb.Datum, uint64, error) {
+	keys, ok := dt.FieldIndex.Keys(field, []gjson.Result{ParseFieldValue(value)})
+	if !ok {
+		return nil, 0, ErrFieldNotIndexed
+	}
+	entries := dt.loadEntries(keys)
+	datumList := make([]*pb.Datum, 0, min(limit, len(entries)))
+	for _, entry := range entries {
+		if len(datumList) >= limit {
+			break
+		}
+		datum, err := entry.Datum()
+		if err != nil {
+			return nil, 0, err
+		}
+		datumList = append(datumList, datum)
+	}
+	return datumList, uint64(len(entries)), nil
+}
+
+// CountByField returns the number of datums for every value of field
+// Expired datums are not counted as in ListByField
+func (dt *Data) CountByField(field string) ([]*pb.FieldValueCount, error) {
+	now := time.Now().Unix()
+	counts, ok := dt.FieldIndex.Counts(field, func(key string) bool {
+		_, live := dt.liveEntry(key, now)
+		return live
+	})
+	if !ok {
+		return nil, ErrFieldNotIndexed
+	}
+	return counts, nil
+}
+
+// searchCandidates does an exact search over entries pre-selected by the field index
+func (dt *Data) searchCandidates(datum *pb.Datum, config *pb.SearchConfig, entries []*DBMapEntry) *Collector {
+	c := NewCollector(datum, config)
+	for _, entry := range entries {
+		datumE, err := entry.Datum()
+		if err != nil || !c.PassesFilters(datumE) {
+			continue
+		}
+		c.Insert(c.Scored(datumE, c.ScoreFunc(datum.Key.Feature, datumE.Key.Feature)))
+	}
+	return c
+}
+
+// filterCandidates returns entries that may pass the filter using the field index
+// It returns false if the filter can not be answered by the field index
+func (dt *Data) filterCandidates(filter *Filter) ([]*DBMapEntry, bool) {
+	if filter == nil || filter.root == nil {
+		return nil, false
+	}
+	keys, ok := candidateKeys(filter.root, &dt.FieldIndex)
+	if !ok {
+		return nil, false
+	}
+	return dt.loadEntries(keys), true
+}
+
+// candidateKeys returns a superset of keys passing node for equality and in-list comparisons of indexed fields
+func candidateKeys(node filterNode, fi *FieldIndex) (map[string]struct{}, bool) {
+	switch n := node.(type) {
+	case *filterComparison:
+		if n.op != "==" && n.op != "IN" {
+			return nil, false
+		}
+		field := "label." + n.path
+		if n.group {
+			field = "group." + n.path
+		}
+		values := make([]gjson.Result, 0, len(n.values))
+		for _, value := range n.values {
+			values = append(values, gjson.Result{Type: value.Type, Str: value.Str, Num: value.Num, Raw: filterValueRaw(value)})
+		}
+		return fi.Keys(field, values)
+	case filterAnd:
+		var result map[string]struct{}
+		for _, child := range n {
+			keys, ok := candidateKeys(child, fi)
+			if !ok {
+				continue
+			}
+			if result == nil {
+				result = keys
+				continue
+			}
+			for key := range result {
+				if _, ok := keys[key]; !ok {
+					delete(result, key)
+				}
+			}
+		}
+		return result, result != nil
+	case filterOr:
+		result := make(map[string]struct{})
+		for _, child := range n {
+			keys, ok := candidateKeys(child, fi)
+			if !ok {
+				return nil, false
+			}
+			for key := range keys {
+				result[key] = struct{}{}
+			}
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+func filterValueRaw(value filterValue) string {
+	switch value.Type {
+	case gjson.True:
+		return "true"
+	case gjson.False:
+		return "false"
+	case gjson.Null:
+		return "null"
+	}
+	return ""
+}
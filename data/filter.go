@@ -0,0 +1,472 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bgokden/go-cache"
+	pb "github.com/bgokden/veri/veriservice"
+	"github.com/tidwall/gjson"
+)
+
+// Filter is a compiled filter expression on Label and GroupLabel JSON of datums
+//
+// Fields are gjson paths prefixed by label or group, e.g. label.price or group.brand.name
+// Comparisons are == (or =), !=, <, <=, >, >=, IN [v1, v2] and a bare field which checks existence
+// Values are numbers, quoted strings, true, false and null
+// Comparisons are combined with AND, OR, NOT and parentheses, && || ! are accepted as well
+// A missing field fails every comparison, arrays match if any element matches
+//
+// Example: label.category == "shoes" AND label.price < 100 AND NOT group.brand IN ["a", "b"]
+type Filter struct {
+	Expression string
+	root       filterNode
+}
+
+type filterNode interface {
+	matches(label []byte, groupLabel []byte) bool
+}
+
+// filterCache keeps compiled filters so that a query is compiled once on every node
+var filterCache = cache.New(10*time.Minute, 1*time.Minute)
+
+// GetFilter returns the compiled filter of an expression, nil for an empty expression
+func GetFilter(expression string) (*Filter, error) {
+	if len(strings.TrimSpace(expression)) == 0 {
+		return nil, nil
+	}
+	if cached, ok := filterCache.Get(expression); ok {
+		return cached.(*Filter), nil
+	}
+	filter, err := CompileFilter(expression)
+	if err != nil {
+		return nil, err
+	}
+	filterCache.Set(expression, filter, cache.DefaultExpiration)
+	return filter, nil
+}
+
+// getSearchFilter returns the filter of a search config
+// An invalid expression is rejected before the search, here it matches nothing
+func getSearchFilter(config *pb.SearchConfig) *Filter {
+	filter, err := GetFilter(config.GetFilter())
+	if err != nil {
+		log.Printf("Filter error: %v\n", err)
+		return &Filter{Expression: config.GetFilter(), root: filterNothing{}}
+	}
+	return filter
+}
+
+// CompileFilter parses a filter expression
+func CompileFilter(expression string) (*Filter, error) {
+	tokens, err := tokenizeFilter(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("Unexpected %v in filter at %v", p.tokens[p.pos].text, p.tokens[p.pos].offset)
+	}
+	return &Filter{
+		Expression: expression,
+		root:       root,
+	}, nil
+}
+
+// Matches is true if datum passes the filter
+func (f *Filter) Matches(datum *pb.Datum) bool {
+	if f == nil || f.root == nil {
+		return true
+	}
+	return f.root.matches(datum.GetValue().GetLabel(), datum.GetKey().GetGroupLabel())
+}
+
+type filterNothing struct{}
+
+func (filterNothing) matches(label []byte, groupLabel []byte) bool { return false }
+
+type filterAnd []filterNode
+
+func (nodes filterAnd) matches(label []byte, groupLabel []byte) bool {
+	for _, node := range nodes {
+		if !node.matches(label, groupLabel) {
+			return false
+		}
+	}
+	return true
+}
+
+type filterOr []filterNode
+
+func (nodes filterOr) matches(label []byte, groupLabel []byte) bool {
+	for _, node := range nodes {
+		if node.matches(label, groupLabel) {
+			return true
+		}
+	}
+	return false
+}
+
+type filterNot struct {
+	node filterNode
+}
+
+func (n filterNot) matches(label []byte, groupLabel []byte) bool {
+	return !n.node.matches(label, groupLabel)
+}
+
+type filterValue struct {
+	Type gjson.Type
+	Str  string
+	Num  float64
+}
+
+// filterComparison compares a field with values, op is empty for an existence check
+type filterComparison struct {
+	group  bool
+	path   string
+	op     string
+	values []filterValue
+}
+
+func (c *filterComparison) matches(label []byte, groupLabel []byte) bool {
+	json := label
+	if c.group {
+		json = groupLabel
+	}
+	result := gjson.GetBytes(json, c.path)
+	if !result.Exists() {
+		return false
+	}
+	if len(c.op) == 0 {
+		return true
+	}
+	if result.IsArray() {
+		matched := false
+		result.ForEach(func(_, element gjson.Result) bool {
+			matched = c.compare(element)
+			return !matched
+		})
+		return matched
+	}
+	return c.compare(result)
+}
+
+func (c *filterComparison) compare(result gjson.Result) bool {
+	switch c.op {
+	case "==", "IN":
+		for _, value := range c.values {
+			if filterEqual(result, value) {
+				return true
+			}
+		}
+		return false
+	case "!=":
+		return !filterEqual(result, c.values[0])
+	}
+	order, ok := filterOrder(result, c.values[0])
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	}
+	return false
+}
+
+func filterEqual(result gjson.Result, value filterValue) bool {
+	if result.Type != value.Type {
+		return false
+	}
+	switch value.Type {
+	case gjson.Number:
+		return result.Num == value.Num
+	case gjson.String:
+		return result.Str == value.Str
+	}
+	return true // true, false and null
+}
+
+// filterOrder compares numbers and strings, other types are not ordered
+func filterOrder(result gjson.Result, value filterValue) (int, bool) {
+	if result.Type != value.Type {
+		return 0, false
+	}
+	switch value.Type {
+	case gjson.Number:
+		if result.Num < value.Num {
+			return -1, true
+		} else if result.Num > value.Num {
+			return 1, true
+		}
+		return 0, true
+	case gjson.String:
+		return strings.Compare(result.Str, value.Str), true
+	}
+	return 0, false
+}
+
+const (
+	filterTokenField = iota
+	filterTokenString
+	filterTokenNumber
+	filterTokenSymbol
+)
+
+type filterToken struct {
+	kind   int
+	text   string
+	offset int
+}
+
+func isFilterFieldChar(ch byte) bool {
+	return ch == '_' || ch == '.' || ch == '-' || ch == '*' || ch == '?' || ch == '#' || ch == '@' || ch == '\\' ||
+		(ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9')
+}
+
+func tokenizeFilter(expression string) ([]filterToken, error) {
+	tokens := make([]filterToken, 0)
+	for i := 0; i < len(expression); {
+		ch := expression[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '"' || ch == '\'':
+			start := i
+			var builder strings.Builder
+			i++
+			for i < len(expression) && expression[i] != ch {
+				if expression[i] == '\\' && i+1 < len(expression) {
+					i++
+				}
+				builder.WriteByte(expression[i])
+				i++
+			}
+			if i >= len(expression) {
+				return nil, fmt.Errorf("Unterminated string in filter at %v", start)
+			}
+			i++
+			tokens = append(tokens, filterToken{kind: filterTokenString, text: builder.String(), offset: start})
+		case (ch >= '0' && ch <= '9') || (ch == '-' && i+1 < len(expression) && expression[i+1] >= '0' && expression[i+1] <= '9'):
+			start := i
+			i++
+			for i < len(expression) && (expression[i] >= '0' && expression[i] <= '9' || expression[i] == '.' ||
+				expression[i] == 'e' || expression[i] == 'E' || expression[i] == '+' || expression[i] == '-') {
+				i++
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenNumber, text: expression[start:i], offset: start})
+		case strings.HasPrefix(expression[i:], "==") || strings.HasPrefix(expression[i:], "!=") ||
+			strings.HasPrefix(expression[i:], "<=") || strings.HasPrefix(expression[i:], ">=") ||
+			strings.HasPrefix(expression[i:], "&&") || strings.HasPrefix(expression[i:], "||"):
+			tokens = append(tokens, filterToken{kind: filterTokenSymbol, text: expression[i : i+2], offset: i})
+			i += 2
+		case strings.IndexByte("()[],<>!=", ch) >= 0:
+			tokens = append(tokens, filterToken{kind: filterTokenSymbol, text: expression[i : i+1], offset: i})
+			i++
+		case isFilterFieldChar(ch):
+			start := i
+			for i < len(expression) && isFilterFieldChar(expression[i]) {
+				if expression[i] == '\\' && i+1 < len(expression) {
+					i++
+				}
+				i++
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenField, text: expression[start:i], offset: start})
+		default:
+			return nil, fmt.Errorf("Unexpected character %q in filter at %v", ch, i)
+		}
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() *filterToken {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+// accept consumes the next token if it is one of the symbols or keywords
+func (p *filterParser) accept(texts ...string) bool {
+	token := p.peek()
+	if token == nil || token.kind == filterTokenString || token.kind == filterTokenNumber {
+		return false
+	}
+	for _, text := range texts {
+		if strings.EqualFold(token.text, text) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *filterParser) expect(text string) error {
+	if !p.accept(text) {
+		if token := p.peek(); token != nil {
+			return fmt.Errorf("Expected %v in filter at %v", text, token.offset)
+		}
+		return fmt.Errorf("Expected %v at the end of filter", text)
+	}
+	return nil
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := filterOr{node}
+	for p.accept("OR", "||") {
+		node, err = p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	node, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	nodes := filterAnd{node}
+	for p.accept("AND", "&&") {
+		node, err = p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *filterParser) parseNot() (filterNode, error) {
+	if p.accept("NOT", "!") {
+		node, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return filterNot{node: node}, nil
+	}
+	if p.accept("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	token := p.peek()
+	if token == nil {
+		return nil, errors.New("Unexpected end of filter")
+	}
+	if token.kind != filterTokenField {
+		return nil, fmt.Errorf("Expected a field in filter at %v", token.offset)
+	}
+	p.pos++
+	comparison := &filterComparison{}
+	switch {
+	case strings.HasPrefix(token.text, "label."):
+		comparison.path = token.text[len("label."):]
+	case strings.HasPrefix(token.text, "group."):
+		comparison.group = true
+		comparison.path = token.text[len("group."):]
+	default:
+		return nil, fmt.Errorf("Field %v in filter should start with label. or group.", token.text)
+	}
+	for _, op := range []string{"==", "=", "!=", "<=", ">=", "<", ">"} {
+		if p.accept(op) {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			if op == "=" {
+				op = "=="
+			}
+			comparison.op = op
+			comparison.values = []filterValue{value}
+			return comparison, nil
+		}
+	}
+	if p.accept("IN") {
+		err := p.expect("[")
+		if err != nil {
+			return nil, err
+		}
+		comparison.op = "IN"
+		for !p.accept("]") {
+			if len(comparison.values) > 0 {
+				err = p.expect(",")
+				if err != nil {
+					return nil, err
+				}
+			}
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			comparison.values = append(comparison.values, value)
+		}
+	}
+	return comparison, nil
+}
+
+func (p *filterParser) parseValue() (filterValue, error) {
+	token := p.peek()
+	if token == nil {
+		return filterValue{}, errors.New("Expected a value at the end of filter")
+	}
+	p.pos++
+	switch token.kind {
+	case filterTokenString:
+		return filterValue{Type: gjson.String, Str: token.text}, nil
+	case filterTokenNumber:
+		number, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return filterValue{}, fmt.Errorf("Invalid number %v in filter at %v", token.text, token.offset)
+		}
+		return filterValue{Type: gjson.Number, Num: number}, nil
+	case filterTokenField:
+		switch token.text {
+		case "true":
+			return filterValue{Type: gjson.True}, nil
+		case "false":
+			return filterValue{Type: gjson.False}, nil
+		case "null":
+			return filterValue{Type: gjson.Null}, nil
+		}
+	}
+	return filterValue{}, fmt.Errorf("Expected a value in filter at %v", token.offset)
+}
@@ -0,0 +1,182 @@
+package data_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	data "github.com/bgokden/veri/data"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+func TestFilter(t *testing.T) {
+	datum := data.NewDatum([]float32{1, 2}, 2, 0, 1, 0,
+		[]byte(`{"brand": "acme"}`),
+		[]byte(`{"category": "shoes", "price": 80, "tags": ["a", "b"], "sale": true}`), 0)
+	cases := map[string]bool{
+		`label.category == "shoes"`:                                true,
+		`label.category = 'shoes' AND label.price < 100`:           true,
+		`label.category == "shoes" && label.price >= 100`:          false,
+		`label.price > 100 OR group.brand == "acme"`:               true,
+		`NOT group.brand IN ["acme", "other"]`:                     false,
+		`!(group.brand IN ["x"]) && label.tags == "b"`:             true,
+		`label.price <= 80 AND label.price >= 80 AND label.sale`:   true,
+		`label.sale == true AND label.missing`:                     false,
+		`label.missing != 1`:                                       false,
+		`label.category > "boots" AND label.category < "sneakers"`: true,
+	}
+	for expression, expected := range cases {
+		filter, err := data.CompileFilter(expression)
+		assert.Nil(t, err, expression)
+		assert.Equal(t, expected, filter.Matches(datum), expression)
+	}
+	for _, expression := range []string{`label.price <`, `label.a OR true`, `price == 1`, `label.a IN [1 2]`, `(label.a`, `label.a == "x`} {
+		_, err := data.CompileFilter(expression)
+		assert.NotNil(t, err, expression)
+	}
+}
+
+func TestDataFilterSearch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	dt, err := data.NewData(&pb.DataConfig{Name: "filtered", TargetN: 1000}, dir)
+	assert.Nil(t, err)
+	defer dt.Close()
+	for i := 0; i < 100; i++ {
+		label := []byte(fmt.Sprintf(`{"i": %v, "parity": "%v"}`, i, []string{"even", "odd"}[i%2]))
+		assert.Nil(t, dt.Insert(data.NewDatum([]float32{float32(i), 1}, 2, 0, 1, 0, []byte("{}"), label, 0), nil))
+	}
+	query := data.NewDatum([]float32{50, 1}, 2, 0, 1, 0, nil, nil, 0)
+	config := data.DefaultSearchConfig()
+	config.Limit = 5
+	config.Filter = `label.parity == "odd" AND label.i < 40`
+	collector := dt.Search(query, config)
+	assert.Equal(t, 5, len(collector.List))
+	assert.Equal(t, `{"i": 39, "parity": "odd"}`, string(collector.List[0].Datum.Value.Label))
+
+	dt.Process(true)
+	config.ScoreFuncName = "AnnoyVectorDistance"
+	collector = dt.SearchAnnoy(query, config)
+	assert.Equal(t, 5, len(collector.List))
+	assert.Equal(t, `{"i": 39, "parity": "odd"}`, string(collector.List[0].Datum.Value.Label))
+
+	result, _, err := dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(result))
+	config.Filter = `label.i <`
+	_, _, err = dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+	assert.NotNil(t, err)
+}
+
+func TestDataFilterExpansion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	dt, err := data.NewData(&pb.DataConfig{Name: "expanded", TargetN: 10000, IndexMetrics: []string{data.AnnoyMetricEuclidean}}, dir)
+	assert.Nil(t, err)
+	defer dt.Close()
+	for i := 0; i < 1000; i++ {
+		label := []byte(fmt.Sprintf(`{"i": %v, "rare": %v, "half": %v}`, i, i%50 == 0, i%2 == 0))
+		assert.Nil(t, dt.Insert(data.NewDatum([]float32{float32(i), 1}, 2, 0, 1, 0, []byte("{}"), label, 0), nil))
+	}
+	dt.Process(true)
+	query := data.NewDatum([]float32{500, 1}, 2, 0, 1, 0, nil, nil, 0)
+	config := data.DefaultSearchConfig()
+	config.ScoreFuncName = "AnnoyVectorDistance"
+	config.Limit = 5
+
+	// Limit is reached by expanding candidates
+	config.Filter = `label.half == true`
+	collector := dt.SearchAnnoy(query, config)
+	assert.Equal(t, 5, len(collector.List))
+	assert.Equal(t, float32(500), collector.List[0].Datum.Key.Feature[0])
+
+	// Selective filters fall back to an exact scan after the budget
+	config.Filter = `label.rare == true`
+	for _, budget := range []uint64{0, 100, 1000} {
+		config.CandidateBudget = budget
+		collector = dt.SearchAnnoy(query, config)
+		assert.Equal(t, 5, len(collector.List))
+		assert.Equal(t, float32(500), collector.List[0].Datum.Key.Feature[0])
+		for _, scoredDatum := range collector.List {
+			assert.Equal(t, 0, int(scoredDatum.Datum.Key.Feature[0])%50)
+		}
+	}
+}
+
+func TestDataFieldIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	config := &pb.DataConfig{
+		Name:          "fields",
+		TargetN:       1000,
+		IndexedFields: []string{"label.parity", "group.brand"},
+	}
+	dt, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	for i := 0; i < 100; i++ {
+		label := []byte(fmt.Sprintf(`{"i": %v, "parity": "%v"}`, i, []string{"even", "odd"}[i%2]))
+		group := []byte(fmt.Sprintf(`{"brand": %v}`, i%10))
+		assert.Nil(t, dt.Insert(data.NewDatum([]float32{float32(i), 1}, 2, 0, 1, 0, group, label, 0), nil))
+	}
+	datumList, count, err := dt.ListByField("label.parity", "odd", 3)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(50), count)
+	assert.Equal(t, 3, len(datumList))
+	_, count, err = dt.ListByField("group.brand", "7", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10), count)
+	_, _, err = dt.ListByField("label.i", "7", 0)
+	assert.Equal(t, data.ErrFieldNotIndexed, err)
+
+	query := data.NewDatum([]float32{50, 1}, 2, 0, 1, 0, nil, nil, 0)
+	searchConfig := data.DefaultSearchConfig()
+	searchConfig.Limit = 3
+	searchConfig.Filter = `group.brand IN [3, 4] AND label.i < 40`
+	collector := dt.Search(query, searchConfig)
+	assert.Equal(t, 3, len(collector.List))
+	assert.Equal(t, `{"i": 34, "parity": "even"}`, string(collector.List[0].Datum.Value.Label))
+
+	assert.Nil(t, dt.Delete(datumList[0]))
+	dt.Close()
+
+	// Field index is rebuilt on replay
+	dt2, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	defer dt2.Close()
+	counts, err := dt2.CountByField("label.parity")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(counts))
+	assert.Equal(t, `"even"`, counts[0].Value)
+	assert.Equal(t, uint64(50), counts[0].Count)
+	assert.Equal(t, uint64(49), counts[1].Count)
+
+	// Raw JSON values keep their text and expired datums are not counted
+	assert.Nil(t, dt2.Insert(data.NewDatum([]float32{100, 1}, 2, 0, 1, 0, nil, []byte(`{"parity": null}`), 0), nil))
+	assert.Nil(t, dt2.Insert(data.NewDatum([]float32{101, 1}, 2, 0, 1, 0, nil, []byte(`{"parity": "odd"}`), 0), &pb.InsertConfig{TTL: 1}))
+	time.Sleep(2 * time.Second)
+	counts, err = dt2.CountByField("label.parity")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(counts))
+	assert.Equal(t, uint64(49), counts[1].Count)
+	assert.Equal(t, "null", counts[2].Value)
+	_, count, err = dt2.ListByField("label.parity", "null", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), count)
+}
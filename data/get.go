@@ -0,0 +1,98 @@
+package data
+
+import (
+	"log"
+	"time"
+
+	"github.com/bgokden/veri/data/gencoder"
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+// Get looks up a datum by its key in local data
+// It returns the expiry in unix seconds, 0 if the datum does not expire
+// A nil key is not found
+func (dt *Data) Get(key *pb.DatumKey) (*pb.Datum, int64, bool) {
+	if key == nil {
+		return nil, 0, false
+	}
+	keyByte, err := gencoder.MarshalKey(key)
+	if err != nil {
+		return nil, 0, false
+	}
+	value, ok := dt.DBMap.Load(GetMapKey(keyByte))
+	if !ok {
+		return nil, 0, false
+	}
+	entry, ok := value.(*DBMapEntry)
+	if !ok || !entry.HasKey(keyByte) {
+		return nil, 0, false
+	}
+	if entry.ExprireAt != 0 && entry.ExprireAt <= time.Now().Unix() {
+		return nil, 0, false
+	}
+	datum, err := entry.Datum()
+	if err != nil {
+		log.Printf("Get decode error: %v\n", err)
+		return nil, 0, false
+	}
+	return datum, entry.ExprireAt, true
+}
+
+// MultiGet looks up datums by their keys in local data, results are in the order of keys
+func (dt *Data) MultiGet(keyList []*pb.DatumKey) ([]*pb.GetResponse, error) {
+	now := time.Now().Unix()
+	result := make([]*pb.GetResponse, len(keyList))
+	for i, key := range keyList {
+		datum, exprireAt, found := dt.Get(key)
+		result[i] = newGetResponse(datum, exprireAt, found, now)
+	}
+	return result, nil
+}
+
+// AggregatedMultiGet is MultiGet asking sources for keys missing in local data
+func (dt *Data) AggregatedMultiGet(keyList []*pb.DatumKey) ([]*pb.GetResponse, error) {
+	result, err := dt.MultiGet(keyList)
+	if err != nil || dt.Sources == nil {
+		return result, err
+	}
+	for _, sourceItem := range dt.Sources.Items() {
+		missing := make([]*pb.DatumKey, 0)
+		missingIndex := make([]int, 0)
+		for i, response := range result {
+			if !response.Found && keyList[i] != nil {
+				missing = append(missing, keyList[i])
+				missingIndex = append(missingIndex, i)
+			}
+		}
+		if len(missing) == 0 {
+			break
+		}
+		source := sourceItem.Object.(DataSource)
+		sourceResult, err := source.MultiGet(missing)
+		if err != nil {
+			log.Printf("MultiGet error from %v: %v\n", source.GetID(), err.Error())
+			continue
+		}
+		for j, response := range sourceResult {
+			if j < len(missingIndex) && response.GetFound() {
+				result[missingIndex[j]] = response
+			}
+		}
+	}
+	return result, nil
+}
+
+func newGetResponse(datum *pb.Datum, exprireAt int64, found bool, now int64) *pb.GetResponse {
+	if !found {
+		return &pb.GetResponse{}
+	}
+	response := &pb.GetResponse{
+		Found:    true,
+		Datum:    datum,
+		ExpireAt: exprireAt,
+	}
+	if exprireAt != 0 {
+		response.TTL = uint64(exprireAt - now)
+	}
+	return response
+}
@@ -0,0 +1,96 @@
+package data
+
+import (
+	"sort"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+// defaultGroupScoreTopN is the number of best members averaged by TopNMean
+const defaultGroupScoreTopN = 3
+
+// GroupScoreFunc aggregates the scores of the members of a group to the score of the group
+// topN is only used by TopNMean
+type GroupScoreFunc func(scores []float64, higherIsBetter bool, topN int) float64
+
+var groupScoreFuncs = map[string]GroupScoreFunc{
+	"Max":           MaxGroupScore,
+	"Mean":          MeanGroupScore,
+	"Sum":           SumGroupScore,
+	"CountWeighted": CountWeightedGroupScore,
+	"TopNMean":      TopNMeanGroupScore,
+}
+
+// GetGroupScoreFunction returns the group score function with name, CountWeighted if it is not known
+func GetGroupScoreFunction(name string) GroupScoreFunc {
+	if function, ok := groupScoreFuncs[name]; ok {
+		return function
+	}
+	return CountWeightedGroupScore
+}
+
+// MaxGroupScore is the best score of the members, the lowest if lower is better
+func MaxGroupScore(scores []float64, higherIsBetter bool, topN int) float64 {
+	return bestFirst(scores, higherIsBetter)[0]
+}
+
+// MeanGroupScore is the average score of the members
+func MeanGroupScore(scores []float64, higherIsBetter bool, topN int) float64 {
+	return SumGroupScore(scores, higherIsBetter, topN) / float64(len(scores))
+}
+
+// SumGroupScore is the sum of the scores of the members
+func SumGroupScore(scores []float64, higherIsBetter bool, topN int) float64 {
+	sum := float64(0)
+	for _, score := range scores {
+		sum += score
+	}
+	return sum
+}
+
+// CountWeightedGroupScore is the average score improved by the number of members
+// It is the sum if higher is better, otherwise the sum divided by the square of the number of members
+func CountWeightedGroupScore(scores []float64, higherIsBetter bool, topN int) float64 {
+	mean := MeanGroupScore(scores, higherIsBetter, topN)
+	if higherIsBetter {
+		return mean * float64(len(scores))
+	}
+	return mean / float64(len(scores))
+}
+
+// TopNMeanGroupScore is the average score of the best topN members
+func TopNMeanGroupScore(scores []float64, higherIsBetter bool, topN int) float64 {
+	if topN <= 0 {
+		topN = defaultGroupScoreTopN
+	}
+	sorted := bestFirst(scores, higherIsBetter)
+	if len(sorted) > topN {
+		sorted = sorted[:topN]
+	}
+	return MeanGroupScore(sorted, higherIsBetter, topN)
+}
+
+// bestFirst returns a copy of scores sorted from the best to the worst
+func bestFirst(scores []float64, higherIsBetter bool) []float64 {
+	sorted := make([]float64, len(scores))
+	copy(sorted, scores)
+	if higherIsBetter {
+		sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+	} else {
+		sort.Float64s(sorted)
+	}
+	return sorted
+}
+
+// GroupScores returns the group score and the group vector score of members with the group score function of config
+func GroupScores(members []*pb.ScoredDatum, config *pb.SearchConfig) (float64, float64) {
+	groupScoreFunc := GetGroupScoreFunction(config.GetGroupScoreFuncName())
+	topN := int(config.GetGroupScoreTopN())
+	scores := make([]float64, len(members))
+	vectorScores := make([]float64, len(members))
+	for i, member := range members {
+		scores[i] = member.GetScore()
+		vectorScores[i] = member.GetVectorScore()
+	}
+	return groupScoreFunc(scores, config.HigherIsBetter, topN), groupScoreFunc(vectorScores, config.HigherIsBetter, topN)
+}
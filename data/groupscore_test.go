@@ -0,0 +1,88 @@
+package data_test
+
+import (
+	"testing"
+
+	data "github.com/bgokden/veri/data"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+func TestGroupScoreFunctions(t *testing.T) {
+	scores := []float64{1, 4, 2, 3}
+	assert.Equal(t, 4.0, data.GetGroupScoreFunction("Max")(scores, true, 0))
+	assert.Equal(t, 1.0, data.GetGroupScoreFunction("Max")(scores, false, 0))
+	assert.Equal(t, 2.5, data.GetGroupScoreFunction("Mean")(scores, true, 0))
+	assert.Equal(t, 10.0, data.GetGroupScoreFunction("Sum")(scores, false, 0))
+	assert.Equal(t, 10.0, data.GetGroupScoreFunction("CountWeighted")(scores, true, 0))
+	assert.Equal(t, 0.625, data.GetGroupScoreFunction("CountWeighted")(scores, false, 0))
+	assert.Equal(t, 3.0, data.GetGroupScoreFunction("TopNMean")(scores, true, 0))
+	assert.Equal(t, 3.5, data.GetGroupScoreFunction("TopNMean")(scores, true, 2))
+	assert.Equal(t, 1.5, data.GetGroupScoreFunction("TopNMean")(scores, false, 2))
+	assert.Equal(t, 0.625, data.GetGroupScoreFunction("")(scores, false, 0))
+}
+
+func TestGroupedAggregation(t *testing.T) {
+	scoredDatumList := []*pb.ScoredDatum{
+		{Datum: data.NewDatum([]float32{1, 0}, 2, 0, 1, 0, []byte(`"x"`), []byte(`"a"`), 0), Score: 0.9},
+		{Datum: data.NewDatum([]float32{2, 0}, 2, 0, 1, 0, []byte(`"y"`), []byte(`"b"`), 0), Score: 0.8},
+		{Datum: data.NewDatum([]float32{3, 0}, 2, 0, 1, 0, []byte(`"y"`), []byte(`"c"`), 0), Score: 0.7},
+		{Datum: data.NewDatum([]float32{4, 0}, 2, 0, 1, 0, []byte(`"y"`), []byte(`"d"`), 0), Score: 0.1},
+	}
+	for _, scoredDatum := range scoredDatumList {
+		scoredDatum.VectorScore = scoredDatum.Score
+	}
+	groups := func(config *pb.SearchConfig) []*pb.ScoredDatum {
+		aggregator := data.NewAggrator(config, true, nil)
+		for _, scoredDatum := range scoredDatumList {
+			aggregator.Insert(scoredDatum)
+		}
+		return aggregator.Result()
+	}
+	config := data.DefaultSearchConfig()
+	config.HigherIsBetter = true
+	config.GroupLimit = 2
+
+	// Sum of the best two members of y is better than x
+	result := groups(config)
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, `"y"`, string(result[0].Datum.Key.GroupLabel))
+	assert.InDelta(t, 1.5, result[0].Score, 1e-9)
+	assert.Equal(t, 2, len(result[0].Members))
+	assert.Equal(t, `"b"`, string(result[0].Members[0].Datum.Value.Label))
+	assert.Equal(t, `"c"`, string(result[0].Members[1].Datum.Value.Label))
+
+	config.GroupScoreFuncName = "Max"
+	result = groups(config)
+	assert.Equal(t, `"x"`, string(result[0].Datum.Key.GroupLabel))
+	assert.InDelta(t, 0.9, result[0].Score, 1e-9)
+	assert.Equal(t, 1, len(result[0].Members))
+
+	// Groups of sources are grouped again by their members
+	aggregator := data.NewAggrator(config, true, nil)
+	aggregator.Insert(result[1])
+	aggregator.Insert(scoredDatumList[3])
+	aggregator.Insert(&pb.ScoredDatum{Datum: data.NewDatum([]float32{5, 0}, 2, 0, 1, 0, []byte(`"y"`), []byte(`"e"`), 0), Score: 0.95, VectorScore: 0.95})
+	merged := aggregator.Result()
+	assert.Equal(t, 1, len(merged))
+	assert.InDelta(t, 0.95, merged[0].Score, 1e-9)
+	assert.Equal(t, `"e"`, string(merged[0].Members[0].Datum.Value.Label))
+	assert.Equal(t, `"b"`, string(merged[0].Members[1].Datum.Value.Label))
+
+	// A prioritized context scores members, the group score is not replaced by the score of the best member
+	config.ScoreFuncName = "VectorMultiplication"
+	config.GroupScoreFuncName = "Sum"
+	context := &pb.SearchContext{
+		Datum:      []*pb.Datum{data.NewDatum([]float32{1, 0}, 2, 0, 1, 0, []byte(`"q"`), []byte(`"q"`), 0)},
+		Prioritize: true,
+	}
+	aggregator = data.NewAggrator(config, true, context)
+	for _, scoredDatum := range scoredDatumList {
+		aggregator.Insert(scoredDatum)
+	}
+	result = aggregator.Result()
+	assert.Equal(t, `"y"`, string(result[0].Datum.Key.GroupLabel))
+	assert.InDelta(t, 7.0, result[0].Score, 1e-9)
+	assert.InDelta(t, 1.0, result[1].Score, 1e-9)
+}
@@ -0,0 +1,419 @@
+package data
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+const (
+	hnswDefaultM              = 16
+	hnswDefaultEfConstruction = 200
+	hnswDefaultEfSearch       = 64
+)
+
+// hnswDistanceFuncs are distances of annoy metrics where lower is closer
+var hnswDistanceFuncs = map[string]func(arr1 []float32, arr2 []float32) float64{
+	AnnoyMetricAngular: func(arr1 []float32, arr2 []float32) float64 {
+		return 1 - CosineSimilarity(arr1, arr2)
+	},
+	AnnoyMetricEuclidean: VectorDistance,
+	AnnoyMetricManhattan: QuickVectorDistance,
+	AnnoyMetricDotProduct: func(arr1 []float32, arr2 []float32) float64 {
+		return -VectorMultiplication(arr1, arr2)
+	},
+}
+
+type hnswNode struct {
+	Key     string
+	Entry   *DBMapEntry
+	Feature []float32
+	Friends [][]uint32
+	Deleted bool
+}
+
+type hnswCandidate struct {
+	ID       uint32
+	Distance float64
+}
+
+// hnswQueue is a heap of candidates, nearest first unless FarthestFirst is set
+type hnswQueue struct {
+	Items         []hnswCandidate
+	FarthestFirst bool
+}
+
+func (q *hnswQueue) Len() int { return len(q.Items) }
+func (q *hnswQueue) Less(i, j int) bool {
+	if q.FarthestFirst {
+		return q.Items[i].Distance > q.Items[j].Distance
+	}
+	return q.Items[i].Distance < q.Items[j].Distance
+}
+func (q *hnswQueue) Swap(i, j int)      { q.Items[i], q.Items[j] = q.Items[j], q.Items[i] }
+func (q *hnswQueue) Push(x interface{}) { q.Items = append(q.Items, x.(hnswCandidate)) }
+func (q *hnswQueue) Pop() interface{} {
+	last := q.Items[len(q.Items)-1]
+	q.Items = q.Items[:len(q.Items)-1]
+	return last
+}
+
+// HNSWIndex is an online hierarchical navigable small world graph
+// Deleted nodes stay in the graph for navigation until Compact runs after more than half of the graph is deleted
+type HNSWIndex struct {
+	sync.RWMutex
+	compactLock    sync.Mutex
+	Metric         string
+	M              int
+	MaxM0          int
+	EfConstruction int
+	EfSearch       int
+	Distance       func(arr1 []float32, arr2 []float32) float64
+	levelMult      float64
+	nodes          []*hnswNode
+	keys           map[string]uint32
+	entryPoint     uint32
+	maxLevel       int
+	deleted        int
+	dim            int
+	rand           *rand.Rand
+}
+
+// NewHNSWIndex creates an empty graph for the first metric in DataConfig
+// Score functions of other metrics are not served by the graph, they are searched exactly
+func NewHNSWIndex(config *pb.DataConfig) *HNSWIndex {
+	m := int(config.GetHnswM())
+	if m <= 1 {
+		m = hnswDefaultM
+	}
+	efConstruction := int(config.GetHnswEfConstruction())
+	if efConstruction <= 0 {
+		efConstruction = hnswDefaultEfConstruction
+	}
+	metric := GetIndexMetrics(config)[0]
+	return &HNSWIndex{
+		Metric:         metric,
+		M:              m,
+		MaxM0:          2 * m,
+		EfConstruction: max(efConstruction, m),
+		EfSearch:       hnswDefaultEfSearch,
+		Distance:       hnswDistanceFuncs[metric],
+		levelMult:      1 / math.Log(float64(m)),
+		keys:           make(map[string]uint32),
+		rand:           rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Add inserts a node into the graph, an existing node with the same key is deleted first
+func (h *HNSWIndex) Add(key string, feature []float32, entry *DBMapEntry) error {
+	h.Lock()
+	defer h.Unlock()
+	if h.dim == 0 {
+		h.dim = len(feature)
+	}
+	if len(feature) != h.dim {
+		return errors.New("Feature dimension does not match the index")
+	}
+	if id, ok := h.keys[key]; ok {
+		h.markDeleted(id)
+	}
+	h.insert(&hnswNode{
+		Key:     key,
+		Entry:   entry,
+		Feature: feature,
+	})
+	return nil
+}
+
+// Delete marks the node with key as deleted
+func (h *HNSWIndex) Delete(key string) {
+	h.Lock()
+	defer h.Unlock()
+	if id, ok := h.keys[key]; ok {
+		h.markDeleted(id)
+	}
+}
+
+// Build does nothing, the graph is always searchable
+func (h *HNSWIndex) Build() error {
+	return nil
+}
+
+// Search returns up to n nearest entries
+// SearchK or Precision of config sets the size of the dynamic candidate list
+// Deleted nodes take places in the candidate list, it grows until n live entries are found or the graph is exhausted
+func (h *HNSWIndex) Search(feature []float32, n int, config *pb.SearchConfig) []*DBMapEntry {
+	h.RLock()
+	defer h.RUnlock()
+	live := len(h.nodes) - h.deleted
+	if n <= 0 || live <= 0 {
+		return nil
+	}
+	ef := max(n, h.EfSearch)
+	if searchK := GetSearchK(config, n, live); searchK > 0 {
+		ef = max(n, searchK)
+	}
+	entryPoints := []hnswCandidate{{
+		ID:       h.entryPoint,
+		Distance: h.Distance(feature, h.nodes[h.entryPoint].Feature),
+	}}
+	for level := h.maxLevel; level > 0; level-- {
+		entryPoints = h.searchLayer(feature, entryPoints, 1, level)
+	}
+	wanted := min(n, live)
+	for {
+		candidates := h.searchLayer(feature, entryPoints, ef, 0)
+		entries := make([]*DBMapEntry, 0, wanted)
+		for _, candidate := range candidates {
+			node := h.nodes[candidate.ID]
+			if node.Deleted {
+				continue
+			}
+			entries = append(entries, node.Entry)
+			if len(entries) >= n {
+				break
+			}
+		}
+		if len(entries) >= wanted || ef >= len(h.nodes) {
+			return entries
+		}
+		ef = min(2*ef, len(h.nodes))
+	}
+}
+
+// Serves is true if the score function has the metric of the graph
+func (h *HNSWIndex) Serves(scoreFuncName string) bool {
+	return annoyMetricOfScoreFunc[scoreFuncName] == h.Metric
+}
+
+// Len is the number of nodes that are not deleted
+func (h *HNSWIndex) Len() int {
+	h.RLock()
+	defer h.RUnlock()
+	return len(h.nodes) - h.deleted
+}
+
+// Online is true, nodes are added and deleted without a rebuild
+func (h *HNSWIndex) Online() bool {
+	return true
+}
+
+// Type is HNSW
+func (h *HNSWIndex) Type() string {
+	return IndexTypeHNSW
+}
+
+// Close drops the graph
+func (h *HNSWIndex) Close() {
+	h.Lock()
+	defer h.Unlock()
+	h.reset()
+}
+
+func (h *HNSWIndex) reset() {
+	h.nodes = nil
+	h.keys = make(map[string]uint32)
+	h.entryPoint = 0
+	h.maxLevel = 0
+	h.deleted = 0
+}
+
+func (h *HNSWIndex) markDeleted(id uint32) {
+	node := h.nodes[id]
+	delete(h.keys, node.Key)
+	node.Deleted = true
+	node.Entry = nil
+	h.deleted++
+}
+
+// needsCompaction is true when deleted nodes dominate the graph
+func (h *HNSWIndex) needsCompaction() bool {
+	return h.deleted > h.EfConstruction && h.deleted*2 > len(h.nodes)
+}
+
+// Compact rebuilds the graph from live nodes when deleted nodes dominate it
+// The new graph is built without blocking searches and writes, writes during the build are applied before the swap
+func (h *HNSWIndex) Compact() {
+	h.compactLock.Lock()
+	defer h.compactLock.Unlock()
+	h.RLock()
+	if !h.needsCompaction() {
+		h.RUnlock()
+		return
+	}
+	snapshot := make([]*hnswNode, len(h.nodes)) // copies of nodes live at the snapshot
+	for id, node := range h.nodes {
+		if !node.Deleted {
+			snapshot[id] = &hnswNode{
+				Key:     node.Key,
+				Entry:   node.Entry,
+				Feature: node.Feature,
+			}
+		}
+	}
+	h.RUnlock()
+	compacted := &HNSWIndex{
+		Metric:         h.Metric,
+		M:              h.M,
+		MaxM0:          h.MaxM0,
+		EfConstruction: h.EfConstruction,
+		EfSearch:       h.EfSearch,
+		Distance:       h.Distance,
+		levelMult:      h.levelMult,
+		keys:           make(map[string]uint32),
+		dim:            h.dim,
+		rand:           rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, node := range snapshot {
+		if node != nil {
+			compacted.insert(node)
+		}
+	}
+	h.Lock()
+	defer h.Unlock()
+	if len(h.nodes) < len(snapshot) {
+		return // closed during the build
+	}
+	for id, node := range h.nodes[:len(snapshot)] {
+		if snapshot[id] != nil && node.Deleted {
+			compacted.markDeleted(compacted.keys[node.Key])
+		}
+	}
+	for _, node := range h.nodes[len(snapshot):] {
+		if node.Deleted {
+			continue
+		}
+		if id, ok := compacted.keys[node.Key]; ok {
+			compacted.markDeleted(id)
+		}
+		compacted.insert(&hnswNode{
+			Key:     node.Key,
+			Entry:   node.Entry,
+			Feature: node.Feature,
+		})
+	}
+	h.nodes = compacted.nodes
+	h.keys = compacted.keys
+	h.entryPoint = compacted.entryPoint
+	h.maxLevel = compacted.maxLevel
+	h.deleted = compacted.deleted
+}
+
+func (h *HNSWIndex) randomLevel() int {
+	return int(math.Floor(-math.Log(1-h.rand.Float64()) * h.levelMult))
+}
+
+func (h *HNSWIndex) insert(node *hnswNode) {
+	level := h.randomLevel()
+	node.Friends = make([][]uint32, level+1)
+	id := uint32(len(h.nodes))
+	h.nodes = append(h.nodes, node)
+	h.keys[node.Key] = id
+	if id == 0 {
+		h.entryPoint = id
+		h.maxLevel = level
+		return
+	}
+	entryPoints := []hnswCandidate{{
+		ID:       h.entryPoint,
+		Distance: h.Distance(node.Feature, h.nodes[h.entryPoint].Feature),
+	}}
+	for l := h.maxLevel; l > level; l-- {
+		entryPoints = h.searchLayer(node.Feature, entryPoints, 1, l)
+	}
+	for l := min(level, h.maxLevel); l >= 0; l-- {
+		candidates := h.searchLayer(node.Feature, entryPoints, h.EfConstruction, l)
+		neighbours := candidates
+		if len(neighbours) > h.M {
+			neighbours = neighbours[:h.M]
+		}
+		node.Friends[l] = make([]uint32, 0, len(neighbours))
+		for _, neighbour := range neighbours {
+			node.Friends[l] = append(node.Friends[l], neighbour.ID)
+			h.link(neighbour.ID, id, l)
+		}
+		entryPoints = candidates
+	}
+	if level > h.maxLevel {
+		h.maxLevel = level
+		h.entryPoint = id
+	}
+}
+
+// link adds a friend to a node and keeps only the closest ones when the list is full
+func (h *HNSWIndex) link(from uint32, to uint32, level int) {
+	node := h.nodes[from]
+	friends := append(node.Friends[level], to)
+	maxM := h.M
+	if level == 0 {
+		maxM = h.MaxM0
+	}
+	if len(friends) > maxM {
+		candidates := make([]hnswCandidate, len(friends))
+		for i, friend := range friends {
+			candidates[i] = hnswCandidate{
+				ID:       friend,
+				Distance: h.Distance(node.Feature, h.nodes[friend].Feature),
+			}
+		}
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].Distance < candidates[j].Distance
+		})
+		friends = friends[:0]
+		for _, candidate := range candidates[:maxM] {
+			friends = append(friends, candidate.ID)
+		}
+	}
+	node.Friends[level] = friends
+}
+
+// searchLayer returns up to ef nearest nodes on a level, sorted nearest first
+func (h *HNSWIndex) searchLayer(feature []float32, entryPoints []hnswCandidate, ef int, level int) []hnswCandidate {
+	visited := make(map[uint32]struct{}, ef*4)
+	candidates := &hnswQueue{}
+	results := &hnswQueue{FarthestFirst: true}
+	for _, entryPoint := range entryPoints {
+		visited[entryPoint.ID] = struct{}{}
+		heap.Push(candidates, entryPoint)
+		heap.Push(results, entryPoint)
+		if results.Len() > ef {
+			heap.Pop(results)
+		}
+	}
+	for candidates.Len() > 0 {
+		current := heap.Pop(candidates).(hnswCandidate)
+		if results.Len() >= ef && current.Distance > results.Items[0].Distance {
+			break
+		}
+		currentNode := h.nodes[current.ID]
+		if level >= len(currentNode.Friends) {
+			continue
+		}
+		for _, friend := range currentNode.Friends[level] {
+			if _, ok := visited[friend]; ok {
+				continue
+			}
+			visited[friend] = struct{}{}
+			distance := h.Distance(feature, h.nodes[friend].Feature)
+			if results.Len() < ef || distance < results.Items[0].Distance {
+				candidate := hnswCandidate{ID: friend, Distance: distance}
+				heap.Push(candidates, candidate)
+				heap.Push(results, candidate)
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+	sorted := results.Items
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Distance < sorted[j].Distance
+	})
+	return sorted
+}
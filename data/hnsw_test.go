@@ -0,0 +1,158 @@
+package data_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"testing"
+
+	data "github.com/bgokden/veri/data"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+func TestDataHNSWSearch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	config := &pb.DataConfig{
+		Name:      "hnsw",
+		Version:   0,
+		TargetN:   1000,
+		IndexType: data.IndexTypeHNSW,
+	}
+	dt, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	defer dt.Close()
+
+	datum, err := load_data_from_json(dt, "./testdata/news_title_embdeddings.json")
+	assert.Nil(t, err)
+	assert.Equal(t, data.IndexTypeHNSW, dt.GetDataInfo().IndexType)
+	assert.Equal(t, 49, dt.Annoyer.Index.Len())
+
+	// Inserts are searchable without a Process run
+	searchConfig := data.DefaultSearchConfig()
+	searchConfig.ScoreFuncName = "AnnoyAngularDistance"
+	searchConfig.Limit = 10
+	collector := dt.SearchAnnoy(datum, searchConfig)
+	assert.Equal(t, searchConfig.Limit, uint32(len(collector.List)))
+
+	err = dt.Insert(datum, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 50, dt.Annoyer.Index.Len())
+	err = dt.Delete(datum)
+	assert.Nil(t, err)
+	assert.Equal(t, 49, dt.Annoyer.Index.Len())
+
+	// Process keeps the online index
+	dt.Process(true)
+	assert.Equal(t, 49, dt.Annoyer.Index.Len())
+}
+
+func TestDataHNSWMetricMismatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	// The graph is angular, euclidean neighbours differ by the norm
+	dt, err := data.NewData(&pb.DataConfig{Name: "hnsw-mismatch", TargetN: 1000, IndexType: data.IndexTypeHNSW}, dir)
+	assert.Nil(t, err)
+	defer dt.Close()
+	r := rand.New(rand.NewSource(7))
+	datums := make([]*pb.Datum, 0, 500)
+	for i := 0; i < 500; i++ {
+		norm := 0.1 + 10*r.Float64()
+		angle := 2 * math.Pi * r.Float64()
+		datum := data.NewDatum([]float32{float32(norm * math.Cos(angle)), float32(norm * math.Sin(angle))}, 2, 0, 1, 0, []byte("{}"), []byte("{}"), 0)
+		assert.Nil(t, dt.Insert(datum, nil))
+		datums = append(datums, datum)
+	}
+	dt.Process(true)
+	assert.True(t, dt.Annoyer.Index.Serves("AnnoyAngularDistance"))
+	assert.False(t, dt.Annoyer.Index.Serves("AnnoyVectorDistance"))
+
+	query := data.NewDatum([]float32{1, 0}, 2, 0, 1, 0, []byte("{}"), []byte("{}"), 0)
+	config := data.DefaultSearchConfig()
+	config.ScoreFuncName = "AnnoyVectorDistance"
+	config.HigherIsBetter = false
+	config.Limit = 10
+	collector := dt.SearchAnnoy(query, config)
+	assert.Equal(t, exactTopKeys(datums, query, "VectorDistance", false, 10), resultKeys(collector.List))
+}
+
+func TestHNSWIndexRecall(t *testing.T) {
+	index := data.NewHNSWIndex(&pb.DataConfig{
+		IndexMetrics: []string{data.AnnoyMetricEuclidean},
+	})
+	random := rand.New(rand.NewSource(42))
+	dim := 8
+	features := make([][]float32, 1000)
+	entries := make(map[*data.DBMapEntry]int, len(features))
+	for i := range features {
+		feature := make([]float32, dim)
+		for j := range feature {
+			feature[j] = random.Float32()
+		}
+		features[i] = feature
+		entry := &data.DBMapEntry{}
+		entries[entry] = i
+		assert.Nil(t, index.Add(fmt.Sprintf("%v", i), feature, entry))
+	}
+	assert.NotNil(t, index.Add("wrong", []float32{0.1}, &data.DBMapEntry{}))
+	assert.Equal(t, len(features), index.Len())
+
+	config := data.DefaultSearchConfig()
+	k := 10
+	found := 0
+	for q := 0; q < 20; q++ {
+		query := features[random.Intn(len(features))]
+		exact := make([]int, len(features))
+		for i := range exact {
+			exact[i] = i
+		}
+		sort.Slice(exact, func(i, j int) bool {
+			return data.VectorDistance(query, features[exact[i]]) < data.VectorDistance(query, features[exact[j]])
+		})
+		expected := make(map[int]bool, k)
+		for _, i := range exact[:k] {
+			expected[i] = true
+		}
+		result := index.Search(query, k, config)
+		assert.Equal(t, k, len(result))
+		for _, entry := range result {
+			if expected[entries[entry]] {
+				found++
+			}
+		}
+	}
+	assert.True(t, float64(found)/float64(20*k) >= 0.9)
+
+	// Deleted nodes are not returned and compaction keeps the rest searchable
+	for i := 0; i < 750; i++ {
+		index.Delete(fmt.Sprintf("%v", i))
+	}
+	assert.Equal(t, 250, index.Len())
+	for compacted := 0; compacted < 2; compacted++ {
+		for _, query := range []int{900, 760} {
+			result := index.Search(features[query], k, config)
+			assert.Equal(t, k, len(result))
+			assert.Equal(t, query, entries[result[0]])
+			for _, entry := range result {
+				assert.True(t, entries[entry] >= 750)
+			}
+		}
+		index.Compact()
+	}
+	assert.Equal(t, 250, index.Len())
+	assert.Equal(t, 250, len(index.Search(features[900], 300, config)))
+}
@@ -0,0 +1,89 @@
+package data
+
+import (
+	"log"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+const (
+	IndexTypeAnnoy = "Annoy"
+	IndexTypeHNSW  = "HNSW"
+)
+
+// Index is an approximate nearest neighbour index over DBMap entries
+// Offline indexes are rebuilt in Process and changes in between are kept in the delta buffer
+// Online indexes are updated on every insert and delete
+type Index interface {
+	// Add adds an entry with its feature under key, the same key replaces the older entry
+	Add(key string, feature []float32, entry *DBMapEntry) error
+	// Delete removes the entry with key, offline indexes ignore it
+	Delete(key string)
+	// Build prepares the index for search after additions, online indexes do nothing
+	Build() error
+	// Compact drops deleted entries of online indexes in Process, offline indexes do nothing
+	Compact()
+	// Search returns up to n entries near feature for the score function of config
+	Search(feature []float32, n int, config *pb.SearchConfig) []*DBMapEntry
+	// Serves is true if the index ranks entries by the metric of the score function
+	Serves(scoreFuncName string) bool
+	// Len is the number of searchable entries
+	Len() int
+	// Online is true if the index is updated on insert and delete
+	Online() bool
+	// Type is the index type given in DataConfig
+	Type() string
+	// Close releases resources of the index
+	Close()
+}
+
+// GetIndexType returns the index type of a data config, Annoy is the default
+func GetIndexType(config *pb.DataConfig) string {
+	switch config.GetIndexType() {
+	case "", IndexTypeAnnoy:
+		return IndexTypeAnnoy
+	case IndexTypeHNSW:
+		return IndexTypeHNSW
+	}
+	log.Printf("Unknown index type %v for data %v, using %v\n", config.GetIndexType(), config.GetName(), IndexTypeAnnoy)
+	return IndexTypeAnnoy
+}
+
+// NewIndex creates an empty index of the type configured in DataConfig
+func NewIndex(config *pb.DataConfig) Index {
+	if GetIndexType(config) == IndexTypeHNSW {
+		return NewHNSWIndex(config)
+	}
+	return NewAnnoyIndexSet(config)
+}
+
+// getIndex returns the current index, it may be nil before the first build
+func (dt *Data) getIndex() Index {
+	dt.Annoyer.RLock()
+	defer dt.Annoyer.RUnlock()
+	return dt.Annoyer.Index
+}
+
+// indexInsert makes a new entry searchable
+// Online indexes take it directly, otherwise it waits in the delta buffer for the next build
+func (dt *Data) indexInsert(key string, feature []float32, entry *DBMapEntry) {
+	index := dt.getIndex()
+	if index != nil && index.Online() {
+		err := index.Add(key, feature, entry)
+		if err != nil {
+			log.Printf("Index insert error: %v\n", err)
+		}
+		return
+	}
+	dt.Delta.Insert(key, entry)
+}
+
+// indexDelete removes an entry from search results
+func (dt *Data) indexDelete(key string) {
+	index := dt.getIndex()
+	if index != nil && index.Online() {
+		index.Delete(key)
+		return
+	}
+	dt.Delta.Delete(key)
+}
@@ -7,13 +7,48 @@ import (
 	pb "github.com/bgokden/veri/veriservice"
 )
 
+// keyLockShards is the number of locks serializing writes of the same key
+const keyLockShards = 64
+
+// InsertBatchSize is the number of datums applied and replicated together in batch inserts
+const InsertBatchSize = 1000
+
+// ErrStaleVersion is returned when a datum is older than the stored datum with the same key
+var ErrStaleVersion = errors.New("Stale version")
+
+// ErrOverTarget is returned when the number of elements reaches the target of the data
+var ErrOverTarget = errors.New("Number of elements is over the target")
+
+// ErrUnderReplicated is returned when a write is applied but replicated to less sources than configured
+var ErrUnderReplicated = errors.New("Replicas is less then Replication Config")
+
+// ErrKeyCollision is returned when a datum has the map key of a stored datum with a different key
+var ErrKeyCollision = errors.New("Key collides with the map key of another datum")
+
+// ErrMissingDatum is returned when a datum, its key or its value is missing
+var ErrMissingDatum = errors.New("Datum is missing")
+
 // Insert inserts data to internal kv store
+// Writes older than the stored version are dropped silently, last writer wins on Version
+// Writes to a key deleted at the same or a higher version return ErrDeleted
 func (dt *Data) Insert(datum *pb.Datum, config *pb.InsertConfig) error {
-	if dt.Config != nil && !dt.Config.NoTarget && dt.N >= dt.Config.TargetN {
-		return errors.New("Number of elements is over the target")
+	_, err := dt.Upsert(datum, config)
+	return err
+}
+
+// Upsert inserts data to internal kv store if its version is not older than the stored version
+// It returns false if the write is rejected as stale, and false with ErrDeleted if the key is deleted
+// Rejected writes are not replicated
+// It returns true with ErrUnderReplicated if the write is applied but not replicated enough
+func (dt *Data) Upsert(datum *pb.Datum, config *pb.InsertConfig) (bool, error) {
+	if dt.Config != nil && !dt.Config.NoTarget && dt.getN() >= dt.Config.TargetN {
+		return false, ErrOverTarget
 	}
 	if dt.Initialized == false {
-		dt.InitData()
+		err := dt.InitData()
+		if err != nil {
+			return false, err
+		}
 	}
 	err := dt.InsertBDMap(datum, config)
 	// var ttlDuration *time.Duration
@@ -39,10 +74,13 @@ func (dt *Data) Insert(datum *pb.Datum, config *pb.InsertConfig) error {
 	// 	// log.Printf("Insert Datum: %v ttl: %v\n", datum, ttlDuration)
 	// 	return txn.Set(keyByte, valueByte)
 	// })
+	if err == ErrStaleVersion {
+		return false, nil
+	}
 	if err != nil {
-		return err
+		return false, err
 	}
-	dt.Dirty = true
+	dt.setDirty(true)
 	if config == nil {
 		config = &pb.InsertConfig{
 			TTL:   0,
@@ -67,8 +105,91 @@ func (dt *Data) Insert(datum *pb.Datum, config *pb.InsertConfig) error {
 			return nil
 		})
 		if counter < dt.Config.ReplicationOnInsert {
-			return errors.New("Replicas is less then Replication Config")
+			return true, ErrUnderReplicated
+		}
+	}
+	return true, nil
+}
+
+// InsertBatch inserts datums with a single store lock acquisition and returns a status per datum
+// Applied datums are replicated to sources in batches when replication on insert is enforced
+// Datums over the target are not inserted, each of them gets ErrOverTarget in its status
+// Deleted datums get ErrDeleted, stale datums are not applied without an error
+func (dt *Data) InsertBatch(datumList []*pb.InsertDatumWithConfig) ([]*pb.InsertStatus, error) {
+	n := dt.getN()
+	if dt.Config != nil && !dt.Config.NoTarget && n >= dt.Config.TargetN {
+		return nil, ErrOverTarget
+	}
+	if dt.Initialized == false {
+		err := dt.InitData()
+		if err != nil {
+			return nil, err
+		}
+	}
+	statusList := make([]*pb.InsertStatus, len(datumList))
+	for i := range statusList {
+		statusList[i] = &pb.InsertStatus{}
+	}
+	accepted := uint64(len(datumList))
+	if dt.Config != nil && !dt.Config.NoTarget {
+		// N is only updated by Process, so datums accepted in this batch are counted against the target
+		if room := dt.Config.TargetN - n; accepted > room {
+			accepted = room
+		}
+		for _, status := range statusList[accepted:] {
+			status.Error = ErrOverTarget.Error()
+		}
+	}
+	errList := dt.InsertBDMapBatch(datumList[:accepted])
+	replicated := make([]*pb.InsertDatumWithConfig, 0, len(datumList))
+	replicatedIndex := make([]int, 0, len(datumList))
+	applied := false
+	for i, err := range errList {
+		if err == ErrStaleVersion {
+			continue
+		}
+		if err != nil {
+			statusList[i].Error = err.Error()
+			continue
+		}
+		statusList[i].Applied = true
+		applied = true
+		config := datumList[i].GetConfig()
+		if dt.Config.EnforceReplicationOnInsert && config.GetCount() == 0 {
+			replicated = append(replicated, &pb.InsertDatumWithConfig{
+				Datum: datumList[i].GetDatum(),
+				Config: &pb.InsertConfig{
+					TTL:   config.GetTTL(),
+					Count: 1,
+				},
+			})
+			replicatedIndex = append(replicatedIndex, i)
+		}
+	}
+	if applied {
+		dt.setDirty(true)
+	}
+	if len(replicated) == 0 {
+		return statusList, nil
+	}
+	counter := uint32(1)
+	dt.RunOnRandomSources(5, func(source DataSource) error {
+		_, err := source.InsertBatch(replicated)
+		if err != nil && CheckIfUnkownError(err) {
+			log.Printf("Sending InsertBatch error %v\n", err.Error())
+		}
+		if err == nil {
+			counter++
+		}
+		if counter >= dt.Config.ReplicationOnInsert {
+			return errors.New("Replication number reached")
+		}
+		return nil
+	})
+	if counter < dt.Config.ReplicationOnInsert {
+		for _, i := range replicatedIndex {
+			statusList[i].Error = ErrUnderReplicated.Error()
 		}
 	}
-	return nil
+	return statusList, nil
 }
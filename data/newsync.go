@@ -1,28 +1,31 @@
 package data
 
 import (
-	"io/ioutil"
+	"bytes"
 	"log"
 	"math/rand"
-	"os"
 	"runtime"
+	"sync"
 	"time"
-	"unsafe"
 
-	"github.com/bgokden/veri/annoyindex"
 	"github.com/bgokden/veri/data/gencoder"
 	"github.com/bgokden/veri/models"
 	"github.com/bgokden/veri/util"
 	pb "github.com/bgokden/veri/veriservice"
 )
 
+// DBMapEntry keeps encoded key and value of a datum in exact size blocks of util.GlobalMemoli
+// Blocks are released when the entry is garbage collected, the collector does not see slices into them
+// so Key and Value must not be kept without the entry, decoding them copies every field
+// Key and Value are read through the methods of the entry which keep it alive until they are decoded
+// Code is the feature encoded by Quantizer, codes only entries have no feature in Key
 type DBMapEntry struct {
 	ExprireAt int64
-	// Datum     *pb.Datum
-	Key       *[]byte
-	KeySize   uintptr
-	Value     *[]byte
-	ValueSize uintptr
+	Key       []byte
+	Value     []byte
+	Code      []byte
+	Quantizer *Quantizer
+	mapKey    string // set if Key has no feature
 }
 
 func NewAllocadtedDatum(datum *pb.Datum) *models.InternalDatum {
@@ -70,45 +73,235 @@ func (dt *Data) InsertBDMap(datum *pb.Datum, config *pb.InsertConfig) error {
 	if config != nil && config.TTL != 0 {
 		exprireAt = time.Now().Unix() + int64(config.TTL)
 	}
-
-	// keyByte, err := GetKeyAsBytes(datum)
-	// if err != nil {
-	// 	return err
-	// }
-	// valueByte, err := GetValueAsBytes(datum)
-	// if err != nil {
-	// 	return err
-	// }
-	keySize := uintptr(10e3)
-	valueSize := uintptr(10e3)
-	keyByteAllocate := (*[]byte)(util.GlobalMemoli.New(keySize))
-	*keyByteAllocate = make([]byte, keySize)
-	_, err := gencoder.MarshalKeyWith(datum.Key, keyByteAllocate)
-	if err != nil {
+	if dt.Store == nil {
+		_, _, err := dt.insertBDMapEntry(datum, exprireAt)
 		return err
 	}
-	valueByteAllocate := (*[]byte)(util.GlobalMemoli.New(valueSize))
-	*valueByteAllocate = make([]byte, valueSize)
-	_, err = gencoder.MarshalValueWith(datum.Value, keyByteAllocate)
+	dt.Store.Lock()
+	defer dt.Store.Unlock()
+	_, entry, err := dt.insertBDMapEntry(datum, exprireAt)
 	if err != nil {
 		return err
 	}
+	return dt.Store.Append(entryRecord(entry))
+}
+
+// InsertBDMapBatch inserts datums under one store lock and appends their records with a single write
+// It returns an error per datum, stale and deleted datums get ErrStaleVersion and ErrDeleted
+func (dt *Data) InsertBDMapBatch(datumList []*pb.InsertDatumWithConfig) []error {
+	errList := make([]error, len(datumList))
+	now := time.Now().Unix()
+	if dt.Store != nil {
+		dt.Store.Lock()
+		defer dt.Store.Unlock()
+	}
+	records := make([]*StoreRecord, 0, len(datumList))
+	for i, datumWithConfig := range datumList {
+		exprireAt := int64(0)
+		if ttl := datumWithConfig.GetConfig().GetTTL(); ttl != 0 {
+			exprireAt = now + int64(ttl)
+		}
+		_, entry, err := dt.insertBDMapEntry(datumWithConfig.GetDatum(), exprireAt)
+		errList[i] = err
+		if err == nil && dt.Store != nil {
+			records = append(records, entryRecord(entry))
+		}
+	}
+	if dt.Store != nil && len(records) > 0 {
+		err := dt.Store.AppendBatch(records)
+		if err != nil {
+			for i := range errList {
+				if errList[i] == nil {
+					errList[i] = err
+				}
+			}
+		}
+	}
+	return errList
+}
+
+// newBDMapEntry encodes datum into blocks sized by gencoder
+func newBDMapEntry(datum *pb.Datum, exprireAt int64) (*DBMapEntry, error) {
+	if datum.GetKey() == nil || datum.GetValue() == nil {
+		return nil, ErrMissingDatum
+	}
+	keyByte := util.GlobalMemoli.NewBytes(int(gencoder.SizeKey(datum.Key)))
+	_, err := gencoder.MarshalKeyWith(datum.Key, &keyByte)
+	if err != nil {
+		util.GlobalMemoli.FreeBytes(keyByte)
+		return nil, err
+	}
+	valueByte := util.GlobalMemoli.NewBytes(int(gencoder.SizeValue(datum.Value)))
+	_, err = gencoder.MarshalValueWith(datum.Value, &valueByte)
+	if err != nil {
+		util.GlobalMemoli.FreeBytes(keyByte)
+		util.GlobalMemoli.FreeBytes(valueByte)
+		return nil, err
+	}
 	entry := &DBMapEntry{
 		ExprireAt: exprireAt,
-		// Datum:     datum,
-		Key:       keyByteAllocate,
-		KeySize:   keySize,
-		Value:     valueByteAllocate,
-		ValueSize: valueSize,
+		Key:       keyByte,
+		Value:     valueByte,
 	}
-
 	runtime.SetFinalizer(entry, func(e *DBMapEntry) {
-		util.GlobalMemoli.Free(unsafe.Pointer(e.Key), e.KeySize)
-		util.GlobalMemoli.Free(unsafe.Pointer(e.Value), e.ValueSize)
+		util.GlobalMemoli.FreeBytes(e.Key)
+		util.GlobalMemoli.FreeBytes(e.Value)
 	})
+	return entry, nil
+}
 
-	dt.DBMap.Store(util.EncodeToString(*keyByteAllocate), entry)
-	return nil
+// newCodedBDMapEntry encodes datum without its feature and keeps code instead
+// key is the map key of datum with its feature
+func newCodedBDMapEntry(key string, datum *pb.Datum, exprireAt int64, code []byte, quantizer *Quantizer) (*DBMapEntry, error) {
+	entry, err := newBDMapEntry(&pb.Datum{
+		Key: &pb.DatumKey{
+			GroupLabel: datum.GetKey().GetGroupLabel(),
+			Size1:      datum.GetKey().GetSize1(),
+			Size2:      datum.GetKey().GetSize2(),
+			Dim1:       datum.GetKey().GetDim1(),
+			Dim2:       datum.GetKey().GetDim2(),
+		},
+		Value: datum.Value,
+	}, exprireAt)
+	if err != nil {
+		return nil, err
+	}
+	entry.Code = code
+	entry.Quantizer = quantizer
+	entry.mapKey = key
+	return entry, nil
+}
+
+// MapKey returns the key of the entry in DBMap
+func (e *DBMapEntry) MapKey() string {
+	if len(e.mapKey) > 0 {
+		return e.mapKey
+	}
+	mapKey := GetMapKey(e.Key)
+	runtime.KeepAlive(e)
+	return mapKey
+}
+
+// HasKey is true if keyByte is the encoded key of the entry
+// Keys of codes only entries have no feature, their map key is trusted
+func (e *DBMapEntry) HasKey(keyByte []byte) bool {
+	if e.CodesOnly() {
+		return true
+	}
+	equal := bytes.Equal(e.Key, keyByte)
+	runtime.KeepAlive(e)
+	return equal
+}
+
+// sameKey is true if other has the key of the entry, map keys are hashes which may collide
+func (e *DBMapEntry) sameKey(other *DBMapEntry) bool {
+	if other.CodesOnly() {
+		return true
+	}
+	equal := e.HasKey(other.Key)
+	runtime.KeepAlive(other)
+	return equal
+}
+
+// CodesOnly is true if the feature of the entry is only kept as a code
+func (e *DBMapEntry) CodesOnly() bool {
+	return len(e.mapKey) > 0
+}
+
+// DatumKey decodes the key of the entry, the feature of a codes only entry is decoded from its code
+func (e *DBMapEntry) DatumKey() (*pb.DatumKey, error) {
+	datumKey, err := ToDatumKey(e.Key)
+	runtime.KeepAlive(e)
+	if err != nil {
+		return nil, err
+	}
+	if e.CodesOnly() {
+		datumKey.Feature = e.Quantizer.Decode(e.Code)
+	}
+	return datumKey, nil
+}
+
+// Datum decodes the datum of the entry like DatumKey
+func (e *DBMapEntry) Datum() (*pb.Datum, error) {
+	datumKey, err := e.DatumKey()
+	if err != nil {
+		return nil, err
+	}
+	datumValue, err := e.DatumValue()
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Datum{
+		Key:   datumKey,
+		Value: datumValue,
+	}, nil
+}
+
+// keyLock returns the lock serializing writes of a map key
+func (dt *Data) keyLock(key string) *sync.Mutex {
+	if len(key) == 0 {
+		return &dt.keyLocks[0]
+	}
+	return &dt.keyLocks[int(key[0])%keyLockShards]
+}
+
+// DatumValue decodes the value of the entry
+func (e *DBMapEntry) DatumValue() (*pb.DatumValue, error) {
+	datumValue, err := ToDatumValue(e.Value)
+	runtime.KeepAlive(e)
+	return datumValue, err
+}
+
+// GetVersion decodes the version of the datum value in entry
+func (e *DBMapEntry) GetVersion() uint64 {
+	datumValue, err := e.DatumValue()
+	if err != nil {
+		return 0
+	}
+	return datumValue.Version
+}
+
+// insertBDMapEntry stores a new entry of datum under its map key and makes it searchable
+// An entry with the same key is replaced unless it is alive and has a higher version
+// A key deleted at the same or a higher version is not inserted until its tombstone expires,
+// after an unversioned delete only the deleted value is rejected
+func (dt *Data) insertBDMapEntry(datum *pb.Datum, exprireAt int64) (string, *DBMapEntry, error) {
+	entry, err := newBDMapEntry(datum, exprireAt)
+	if err != nil {
+		return "", nil, err
+	}
+	key := entry.MapKey()
+	entry, err = dt.quantizeEntry(key, entry, datum)
+	if err != nil {
+		return "", nil, err
+	}
+	return dt.storeBDMapEntry(key, datum, entry)
+}
+
+// storeBDMapEntry stores entry of datum under key like insertBDMapEntry
+// An entry of a different key with the same map key is not replaced, ErrKeyCollision is returned
+func (dt *Data) storeBDMapEntry(key string, datum *pb.Datum, entry *DBMapEntry) (string, *DBMapEntry, error) {
+	lock := dt.keyLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+	if dt.Tombstones.Covers(key, datum.GetValue(), time.Now().Unix()) {
+		return key, nil, ErrDeleted
+	}
+	if old, ok := dt.DBMap.Load(key); ok {
+		if oldEntry, ok := old.(*DBMapEntry); ok {
+			if !oldEntry.sameKey(entry) {
+				return key, oldEntry, ErrKeyCollision
+			}
+			expired := oldEntry.ExprireAt != 0 && oldEntry.ExprireAt <= time.Now().Unix()
+			if !expired && oldEntry.GetVersion() > datum.GetValue().GetVersion() {
+				return key, oldEntry, ErrStaleVersion
+			}
+		}
+	}
+	dt.DBMap.Store(key, entry)
+	dt.FieldIndex.Insert(key, datum)
+	dt.indexInsert(key, datum.Key.Feature, entry)
+	return key, entry, nil
 }
 
 func (dt *Data) DeleteBDMap(datum *pb.Datum) error {
@@ -116,20 +309,68 @@ func (dt *Data) DeleteBDMap(datum *pb.Datum) error {
 	if err != nil {
 		return err
 	}
-	dt.DBMap.Delete(util.EncodeToString(keyByte))
+	if dt.Store == nil {
+		dt.deleteBDMapEntry(GetMapKey(keyByte))
+		return nil
+	}
+	dt.Store.Lock()
+	defer dt.Store.Unlock()
+	dt.deleteBDMapEntry(GetMapKey(keyByte))
 	// FreeAllocadtedDatum(datum)
-	return nil
+	return dt.Store.Append(&StoreRecord{
+		Op:  storeOpDelete,
+		Key: keyByte,
+	})
+}
+
+// DeleteBDMapKey deletes the entry of a map key
+func (dt *Data) DeleteBDMapKey(key string) error {
+	if dt.Store == nil {
+		dt.deleteBDMapEntry(key)
+		return nil
+	}
+	dt.Store.Lock()
+	defer dt.Store.Unlock()
+	dt.deleteBDMapEntry(key)
+	return dt.Store.Append(&StoreRecord{
+		Op:  storeOpDeleteKey,
+		Key: []byte(key),
+	})
+}
+
+func (dt *Data) deleteBDMapEntry(key string) {
+	dt.DBMap.Delete(key)
+	dt.FieldIndex.Delete(key)
+	dt.indexDelete(key)
+}
+
+// deleteExpiredBDMapEntry deletes entry of key unless it is replaced after it is found expired
+func (dt *Data) deleteExpiredBDMapEntry(key string, entry *DBMapEntry) {
+	lock := dt.keyLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+	if current, ok := dt.DBMap.Load(key); ok && current == entry {
+		dt.deleteBDMapEntry(key)
+	}
 }
 
 func (dt *Data) LoopDBMap(entryFunction func(entry *DBMapEntry) error) error {
+	return dt.loopDBMapWithKey(func(key string, entry *DBMapEntry) error {
+		return entryFunction(entry)
+	})
+}
+
+// loopDBMapWithKey is LoopDBMap with the map key of each entry
+func (dt *Data) loopDBMapWithKey(entryFunction func(key string, entry *DBMapEntry) error) error {
 	var lastError error
 	dt.DBMap.Range(func(key, value interface{}) bool {
 		if mapEntry, ok := value.(*DBMapEntry); ok {
+			keyString, _ := key.(string)
 			if mapEntry.ExprireAt != 0 && mapEntry.ExprireAt <= time.Now().Unix() {
-				dt.DBMap.Delete(key)
+				dt.deleteExpiredBDMapEntry(keyString, mapEntry)
 				return true
 			}
-			err := entryFunction(mapEntry)
+			err := entryFunction(keyString, mapEntry)
 			if err != nil {
 				lastError = err
 				return false
@@ -140,13 +381,28 @@ func (dt *Data) LoopDBMap(entryFunction func(entry *DBMapEntry) error) error {
 	return lastError
 }
 
+// sourcedDatum is a datum sent to sources by Process with the map key of its entry
+type sourcedDatum struct {
+	key   string
+	datum *pb.InsertDatumWithConfig
+}
+
+// Process calculates statistics, rebuilds the index, trains the quantizer and sends datums to sources
+// Statistics of the previous run are read and the new ones are written under the data lock
 func (dt *Data) Process(force bool) error {
-	if getCurrentTime()-dt.Timestamp >= 60 || force {
+	dt.RLock()
+	timestamp := dt.Timestamp
+	previousN := dt.N
+	previousAvg := dt.Avg
+	previousMaxDistance := dt.MaxDistance
+	alive := dt.Alive
+	dt.RUnlock()
+	if getCurrentTime()-timestamp >= 60 || force {
 		localInfo := dt.GetDataInfo()
 		localN := localInfo.N
 		config := dt.GetConfig()
 		diffMap, limit := dt.DataSourceDiffMap()
-		datumStream := make(chan *pb.InsertDatumWithConfig, limit)
+		datumStream := make(chan *sourcedDatum, limit)
 		defer close(datumStream)
 		insertionCounter := uint64(0)
 		fraction := float64(0)
@@ -156,7 +412,8 @@ func (dt *Data) Process(force bool) error {
 			go func() {
 				deleted := uint64(0)
 				counter := 0
-				for datum := range datumStream {
+				for sourced := range datumStream {
+					datum := sourced.datum
 					for id, count := range diffMap {
 						if countMap[id] < count {
 							if sourceItem, ok := dt.Sources.Get(id); ok {
@@ -168,9 +425,11 @@ func (dt *Data) Process(force bool) error {
 									if err == nil {
 										counter++
 									}
-									if err == nil && (!dt.Alive || isEvictionOn(localInfo, config, deleted)) {
+									// A source with a newer version or a tombstone does not need the local copy either
+									delivered := err == nil || err == ErrStaleVersion || err == ErrDeleted
+									if delivered && (!alive || isEvictionOn(localInfo, config, deleted)) {
 										countMap[id]++
-										dt.DeleteBDMap(datum.Datum)
+										dt.DeleteBDMapKey(sourced.key)
 										deleted++
 									}
 								}
@@ -185,28 +444,34 @@ func (dt *Data) Process(force bool) error {
 		maxDistance := 0.0
 		avg := make([]float32, 0)
 		hist := make([]float32, 64)
-		nFloat := float32(dt.N)
+		nFloat := float32(previousN)
 		if nFloat == 0 {
 			nFloat = 1
 		}
 		histUnit := 1 / nFloat
-		newDataIndex := make([]*DBMapEntry, max(1000, int(dt.N)))
-		var newAnnoyIndex annoyindex.AnnoyIndexAngular
-		var newTempFileName string
+		buildSequence := dt.Delta.Mark()
+		currentIndex := dt.getIndex()
+		rebuild := alive && (currentIndex == nil || !currentIndex.Online())
+		var newIndex Index
+		if rebuild {
+			newIndex = NewIndex(config)
+		}
+		trainQuantizer := dt.needsQuantizerTraining(config, localN)
+		var sample [][]float32
 
-		err := dt.LoopDBMap(func(entry *DBMapEntry) error {
+		err := dt.loopDBMapWithKey(func(key string, entry *DBMapEntry) error {
 			n++
-			datumKey, err := ToDatumKey(*(entry.Key))
+			datumKey, err := entry.DatumKey()
 			if err != nil {
 				return err
 			}
 			avg = CalculateAverage(avg, datumKey.Feature, nFloat)
-			distance = VectorDistance(dt.Avg, datumKey.Feature)
+			distance = VectorDistance(previousAvg, datumKey.Feature)
 			if distance > maxDistance {
 				maxDistance = distance
 			}
-			if dt.MaxDistance != 0 {
-				index := int((distance / dt.MaxDistance) * 64)
+			if previousMaxDistance != 0 {
+				index := int((distance / previousMaxDistance) * 64)
 				if index >= 64 {
 					index = 63
 				}
@@ -215,34 +480,31 @@ func (dt *Data) Process(force bool) error {
 				}
 				hist[index] += histUnit
 			}
-			i := int(n - 1)
-			if dt.Alive && i < len(newDataIndex) {
-				if newAnnoyIndex == nil {
-					// newAnnoyIndex = annoyindex.NewAnnoyIndexEuclidean(len(datum.Key.Feature))
-					newAnnoyIndex = annoyindex.NewAnnoyIndexAngular(len(datumKey.Feature))
-					tmpfile, err := ioutil.TempFile("", "annoy")
-					if err == nil {
-						newTempFileName = tmpfile.Name()
-						newAnnoyIndex.OnDiskBuild(newTempFileName)
-					}
-
+			if trainQuantizer {
+				sample = addToSample(sample, datumKey.Feature, n)
+			}
+			if newIndex != nil {
+				err = newIndex.Add(key, datumKey.Feature, entry)
+				if err != nil {
+					return err
 				}
-				newAnnoyIndex.AddItem(i, datumKey.Feature)
-				newDataIndex[i] = entry
 			}
-			if !dt.Alive || (insertionCounter < limit && rand.Float64() < fraction) {
+			if !alive || (insertionCounter < limit && rand.Float64() < fraction) {
 				config := InsertConfigFromExpireAt(uint64(entry.ExprireAt))
 				if config.TTL > 10 {
-					datumValue, err := ToDatumValue(*(entry.Value))
+					datumValue, err := entry.DatumValue()
 					if err != nil {
 						return err
 					}
-					datumStream <- &pb.InsertDatumWithConfig{
-						Datum: &pb.Datum{
-							Key:   datumKey,
-							Value: datumValue,
+					datumStream <- &sourcedDatum{
+						key: key,
+						datum: &pb.InsertDatumWithConfig{
+							Datum: &pb.Datum{
+								Key:   datumKey,
+								Value: datumValue,
+							},
+							Config: config,
 						},
-						Config: config,
 					}
 					insertionCounter++
 				}
@@ -250,34 +512,48 @@ func (dt *Data) Process(force bool) error {
 			return nil
 		})
 		if err != nil {
+			if newIndex != nil {
+				newIndex.Close()
+			}
 			return err
 		}
+		dt.Lock()
 		dt.Avg = avg
 		dt.Hist = hist
 		dt.MaxDistance = maxDistance
 		dt.N = n
 		dt.Timestamp = getCurrentTime()
-		if newAnnoyIndex != nil {
+		dt.Unlock()
+		if !rebuild && currentIndex != nil {
+			// Online indexes drop deleted entries here instead of on the write path
+			currentIndex.Compact()
+		}
+		if newIndex != nil {
 			start := time.Now()
-			newAnnoyIndex.Build(-1) // Previosly 10, -1 creates index dynamically
-			elapsed := time.Since(start)
-			log.Printf("Building annoy index took %s", elapsed)
-			// log.Printf("Updating index. len: %v\n", len(newDataIndex))
-			dt.Annoyer.Lock()
-			if dt.Annoyer.DataIndex != nil {
-				dt.Annoyer.AnnoyIndex.Unload() // Not sure if this is needed
-				annoyindex.DeleteAnnoyIndexAngular(dt.Annoyer.AnnoyIndex)
+			err = newIndex.Build()
+			if err != nil {
+				newIndex.Close()
+				return err
 			}
-			oldFile := dt.Annoyer.BuildFileName
-			dt.Annoyer.BuildFileName = newTempFileName
-			dt.Annoyer.AnnoyIndex = newAnnoyIndex
-			dt.Annoyer.DataIndex = &newDataIndex
+			buildDuration := time.Since(start)
+			dt.Annoyer.Lock()
+			oldIndex := dt.Annoyer.Index
+			dt.Annoyer.Index = newIndex
+			dt.Annoyer.BuildDuration = buildDuration
+			dt.Delta.Trim(buildSequence)
 			dt.Annoyer.Unlock()
-			if len(oldFile) > 0 {
-				os.Remove(oldFile)
+			if oldIndex != nil {
+				oldIndex.Close()
+			}
+		}
+		if trainQuantizer && len(sample) > 0 {
+			err = dt.trainQuantizer(config, sample, n)
+			if err != nil {
+				log.Printf("Data %v quantizer error: %v\n", config.Name, err)
 			}
 		}
+		dt.Tombstones.Expire(time.Now().Unix())
 	}
-	dt.Dirty = false
+	dt.setDirty(false)
 	return nil
 }
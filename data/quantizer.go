@@ -0,0 +1,472 @@
+package data
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"log"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+const (
+	QuantizationPQ = "PQ"
+	QuantizationSQ = "SQ"
+
+	quantizerTrainingSize    = 2048
+	quantizerIterations      = 8
+	quantizerDefaultSubDim   = 8
+	quantizerMaxCentroids    = 256
+	quantizedRerankFactor    = 4
+	quantizerRetrainIncrease = 2 // retrain when data grows this many times since the last training
+)
+
+const (
+	distanceTableEuclidean = iota
+	distanceTableManhattan
+	distanceTableDot
+	distanceTableCosine
+	distanceTableAngular
+)
+
+// distanceTableKinds maps exact score functions to the table that approximates them
+var distanceTableKinds = map[string]int{
+	"VectorDistance":       distanceTableEuclidean,
+	"QuickVectorDistance":  distanceTableManhattan,
+	"VectorMultiplication": distanceTableDot,
+	"CosineSimilarity":     distanceTableCosine,
+	"AngularDistance":      distanceTableAngular,
+}
+
+// Quantizer compresses features into one byte per subspace
+// PQ learns centroids of every subspace with k-means
+// SQ uses single dimension subspaces with 256 evenly spaced values between min and max
+type Quantizer struct {
+	Type      string
+	Dim       int
+	K         int
+	Offsets   []int
+	Centroids [][]float32 // K centroids of each subspace, flattened
+	Norms     [][]float64 // squared norms of centroids
+	Min       []float32   // SQ only
+	Step      []float32   // SQ only
+	TrainedN  uint64
+}
+
+// Quantized keeps the current quantizer, entries keep their codes
+type Quantized struct {
+	sync.RWMutex
+	Quantizer *Quantizer
+}
+
+// GetQuantization returns the quantization type of a data config, empty if disabled
+func GetQuantization(config *pb.DataConfig) string {
+	switch config.GetQuantization() {
+	case QuantizationPQ, QuantizationSQ:
+		return config.GetQuantization()
+	case "":
+		return ""
+	}
+	log.Printf("Unknown quantization %v for data %v\n", config.GetQuantization(), config.GetName())
+	return ""
+}
+
+// TrainQuantizer trains a quantizer of the configured type on sample features
+func TrainQuantizer(config *pb.DataConfig, sample [][]float32) (*Quantizer, error) {
+	if len(sample) == 0 || len(sample[0]) == 0 {
+		return nil, errors.New("Quantizer needs a sample to train")
+	}
+	dim := len(sample[0])
+	for _, feature := range sample {
+		if len(feature) != dim {
+			return nil, errors.New("Quantizer sample has different dimensions")
+		}
+	}
+	var q *Quantizer
+	if GetQuantization(config) == QuantizationSQ {
+		q = trainScalarQuantizer(sample, dim)
+	} else {
+		q = trainProductQuantizer(config, sample, dim)
+	}
+	q.Norms = make([][]float64, len(q.Centroids))
+	for s := range q.Centroids {
+		subDim := q.Offsets[s+1] - q.Offsets[s]
+		q.Norms[s] = make([]float64, q.K)
+		for c := 0; c < q.K; c++ {
+			centroid := q.Centroids[s][c*subDim : (c+1)*subDim]
+			q.Norms[s][c] = VectorMultiplication(centroid, centroid)
+		}
+	}
+	return q, nil
+}
+
+func trainScalarQuantizer(sample [][]float32, dim int) *Quantizer {
+	q := &Quantizer{
+		Type:      QuantizationSQ,
+		Dim:       dim,
+		K:         quantizerMaxCentroids,
+		Offsets:   make([]int, dim+1),
+		Centroids: make([][]float32, dim),
+		Min:       make([]float32, dim),
+		Step:      make([]float32, dim),
+	}
+	for d := 0; d < dim; d++ {
+		q.Offsets[d+1] = d + 1
+		minValue, maxValue := sample[0][d], sample[0][d]
+		for _, feature := range sample {
+			if feature[d] < minValue {
+				minValue = feature[d]
+			}
+			if feature[d] > maxValue {
+				maxValue = feature[d]
+			}
+		}
+		q.Min[d] = minValue
+		q.Step[d] = (maxValue - minValue) / float32(q.K-1)
+		q.Centroids[d] = make([]float32, q.K)
+		for c := 0; c < q.K; c++ {
+			q.Centroids[d][c] = minValue + float32(c)*q.Step[d]
+		}
+	}
+	return q
+}
+
+func trainProductQuantizer(config *pb.DataConfig, sample [][]float32, dim int) *Quantizer {
+	subspaces := int(config.GetPqSubspaces())
+	if subspaces <= 0 {
+		subspaces = max(1, dim/quantizerDefaultSubDim)
+	}
+	subspaces = min(subspaces, dim)
+	k := int(config.GetPqCentroids())
+	if k <= 1 || k > quantizerMaxCentroids {
+		k = quantizerMaxCentroids
+	}
+	k = min(k, len(sample))
+	q := &Quantizer{
+		Type:      QuantizationPQ,
+		Dim:       dim,
+		K:         k,
+		Offsets:   make([]int, subspaces+1),
+		Centroids: make([][]float32, subspaces),
+	}
+	for s := 0; s < subspaces; s++ {
+		q.Offsets[s+1] = (s + 1) * dim / subspaces
+	}
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for s := 0; s < subspaces; s++ {
+		q.Centroids[s] = kMeans(sample, q.Offsets[s], q.Offsets[s+1], k, random)
+	}
+	return q
+}
+
+// kMeans clusters the [start, end) part of sample features and returns flattened centroids
+func kMeans(sample [][]float32, start int, end int, k int, random *rand.Rand) []float32 {
+	subDim := end - start
+	centroids := make([]float32, k*subDim)
+	for c, i := range random.Perm(len(sample))[:k] {
+		copy(centroids[c*subDim:], sample[i][start:end])
+	}
+	assignments := make([]int, len(sample))
+	sums := make([]float64, k*subDim)
+	counts := make([]int, k)
+	for iteration := 0; iteration < quantizerIterations; iteration++ {
+		for i, feature := range sample {
+			assignments[i] = nearestCentroid(feature[start:end], centroids, subDim)
+		}
+		for i := range sums {
+			sums[i] = 0
+		}
+		for c := range counts {
+			counts[c] = 0
+		}
+		for i, feature := range sample {
+			c := assignments[i]
+			counts[c]++
+			for d := 0; d < subDim; d++ {
+				sums[c*subDim+d] += float64(feature[start+d])
+			}
+		}
+		for c := 0; c < k; c++ {
+			if counts[c] == 0 {
+				// Empty cluster is moved to a random point
+				copy(centroids[c*subDim:(c+1)*subDim], sample[random.Intn(len(sample))][start:end])
+				continue
+			}
+			for d := 0; d < subDim; d++ {
+				centroids[c*subDim+d] = float32(sums[c*subDim+d] / float64(counts[c]))
+			}
+		}
+	}
+	return centroids
+}
+
+func nearestCentroid(subFeature []float32, centroids []float32, subDim int) int {
+	best := 0
+	bestDistance := float32(math.MaxFloat32)
+	for c := 0; c*subDim < len(centroids); c++ {
+		distance := float32(0)
+		for d := 0; d < subDim; d++ {
+			diff := subFeature[d] - centroids[c*subDim+d]
+			distance += diff * diff
+		}
+		if distance < bestDistance {
+			best = c
+			bestDistance = distance
+		}
+	}
+	return best
+}
+
+// Encode returns the code of a feature, nil if dimension does not match
+func (q *Quantizer) Encode(feature []float32) []byte {
+	if len(feature) != q.Dim {
+		return nil
+	}
+	code := make([]byte, len(q.Centroids))
+	for s := range q.Centroids {
+		if q.Type == QuantizationSQ {
+			c := 0
+			if q.Step[s] > 0 {
+				c = int(math.Round(float64((feature[s] - q.Min[s]) / q.Step[s])))
+			}
+			code[s] = byte(max(0, min(c, q.K-1)))
+			continue
+		}
+		start, end := q.Offsets[s], q.Offsets[s+1]
+		code[s] = byte(nearestCentroid(feature[start:end], q.Centroids[s], end-start))
+	}
+	return code
+}
+
+// Decode returns the approximate feature of a code
+func (q *Quantizer) Decode(code []byte) []float32 {
+	feature := make([]float32, q.Dim)
+	for s := range q.Centroids {
+		start, end := q.Offsets[s], q.Offsets[s+1]
+		subDim := end - start
+		c := int(code[s])
+		copy(feature[start:end], q.Centroids[s][c*subDim:(c+1)*subDim])
+	}
+	return feature
+}
+
+// DistanceTable scores codes against a query without decoding them
+type DistanceTable struct {
+	Kind      int
+	Tables    [][]float64
+	Norms     [][]float64
+	QueryNorm float64
+}
+
+// NewDistanceTable precomputes partial scores of query against every centroid
+// It returns nil if the score function can not be approximated
+func (q *Quantizer) NewDistanceTable(query []float32, scoreFuncName string) *DistanceTable {
+	kind, ok := distanceTableKinds[scoreFuncName]
+	if !ok || len(query) != q.Dim {
+		return nil
+	}
+	t := &DistanceTable{
+		Kind:      kind,
+		Tables:    make([][]float64, len(q.Centroids)),
+		Norms:     q.Norms,
+		QueryNorm: math.Sqrt(VectorMultiplication(query, query)),
+	}
+	for s := range q.Centroids {
+		start, end := q.Offsets[s], q.Offsets[s+1]
+		subDim := end - start
+		subQuery := query[start:end]
+		t.Tables[s] = make([]float64, q.K)
+		for c := 0; c < q.K; c++ {
+			centroid := q.Centroids[s][c*subDim : (c+1)*subDim]
+			switch kind {
+			case distanceTableEuclidean:
+				distance := 0.0
+				for d := 0; d < subDim; d++ {
+					diff := float64(subQuery[d] - centroid[d])
+					distance += diff * diff
+				}
+				t.Tables[s][c] = distance
+			case distanceTableManhattan:
+				t.Tables[s][c] = QuickVectorDistance(subQuery, centroid)
+			default:
+				t.Tables[s][c] = VectorMultiplication(subQuery, centroid)
+			}
+		}
+	}
+	return t
+}
+
+// Score returns the approximate score of a code in the scale of the exact score function
+func (t *DistanceTable) Score(code []byte) float64 {
+	sum := 0.0
+	norm := 0.0
+	for s, c := range code {
+		sum += t.Tables[s][c]
+		if t.Kind == distanceTableCosine || t.Kind == distanceTableAngular {
+			norm += t.Norms[s][c]
+		}
+	}
+	switch t.Kind {
+	case distanceTableEuclidean:
+		return math.Sqrt(sum)
+	case distanceTableCosine, distanceTableAngular:
+		similarity := 0.0
+		if norm > 0 && t.QueryNorm > 0 {
+			similarity = math.Max(-1, math.Min(1, sum/(t.QueryNorm*math.Sqrt(norm))))
+		}
+		if t.Kind == distanceTableAngular {
+			return 1.0 - (math.Acos(similarity) / math.Pi)
+		}
+		return similarity
+	}
+	return sum
+}
+
+// addToSample keeps a uniform sample of at most quantizerTrainingSize features, n is the number of features seen
+func addToSample(sample [][]float32, feature []float32, n uint64) [][]float32 {
+	if len(sample) < quantizerTrainingSize {
+		return append(sample, feature)
+	}
+	if r := rand.Int63n(int64(n)); r < quantizerTrainingSize {
+		sample[r] = feature
+	}
+	return sample
+}
+
+// getQuantizer returns the current quantizer, nil if it is not trained
+func (dt *Data) getQuantizer() *Quantizer {
+	dt.Quantized.RLock()
+	defer dt.Quantized.RUnlock()
+	return dt.Quantized.Quantizer
+}
+
+// codesOnly is true if quantized entries keep only their codes
+func (dt *Data) codesOnly() bool {
+	return dt.Config != nil && dt.Config.CodesOnly && len(GetQuantization(dt.Config)) > 0
+}
+
+// quantizeEntry sets the code of a new entry of datum if a quantizer is trained
+// With codes only it returns a new entry without the feature, key is the map key of datum
+func (dt *Data) quantizeEntry(key string, entry *DBMapEntry, datum *pb.Datum) (*DBMapEntry, error) {
+	quantizer := dt.getQuantizer()
+	if quantizer == nil {
+		return entry, nil
+	}
+	code := quantizer.Encode(datum.Key.Feature)
+	if code == nil {
+		return entry, nil
+	}
+	if dt.codesOnly() {
+		return newCodedBDMapEntry(key, datum, entry.ExprireAt, code, quantizer)
+	}
+	entry.Code = code
+	entry.Quantizer = quantizer
+	return entry, nil
+}
+
+// requantizeEntry replaces entry with an entry encoded by the current quantizer
+// Nothing is done if entry is already encoded or it is no longer the entry of key
+// Codes only entries are not encoded again, the decoded feature would add the error of another code
+func (dt *Data) requantizeEntry(key string, entry *DBMapEntry) error {
+	if entry.CodesOnly() {
+		return nil
+	}
+	lock := dt.keyLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+	if current, ok := dt.DBMap.Load(key); !ok || current != entry || entry.Quantizer == dt.getQuantizer() {
+		return nil
+	}
+	datum, err := entry.Datum()
+	if err != nil {
+		return err
+	}
+	newEntry, err := newBDMapEntry(datum, entry.ExprireAt)
+	if err != nil {
+		return err
+	}
+	newEntry, err = dt.quantizeEntry(key, newEntry, datum)
+	if err != nil {
+		return err
+	}
+	dt.DBMap.Store(key, newEntry)
+	return nil
+}
+
+// needsQuantizerTraining is true if quantization is on and the quantizer is missing or outgrown
+// Codes only data keeps its first quantizer, its entries have no features to encode again
+func (dt *Data) needsQuantizerTraining(config *pb.DataConfig, n uint64) bool {
+	if len(GetQuantization(config)) == 0 {
+		return false
+	}
+	quantizer := dt.getQuantizer()
+	if quantizer != nil && dt.codesOnly() {
+		return false
+	}
+	return quantizer == nil || quantizer.Type != GetQuantization(config) || n >= quantizer.TrainedN*quantizerRetrainIncrease
+}
+
+// trainQuantizer trains a new quantizer on sample and encodes every entry with it
+// The quantizer is logged before entries encoded with it so that the store can decode them on replay
+func (dt *Data) trainQuantizer(config *pb.DataConfig, sample [][]float32, n uint64) error {
+	start := time.Now()
+	quantizer, err := TrainQuantizer(config, sample)
+	if err != nil {
+		return err
+	}
+	quantizer.TrainedN = n
+	err = dt.setQuantizer(quantizer)
+	if err != nil {
+		return err
+	}
+	err = dt.loopDBMapWithKey(dt.requantizeEntry)
+	if err != nil {
+		return err
+	}
+	log.Printf("Training %v quantizer with %v subspaces on %v samples took %s", quantizer.Type, len(quantizer.Centroids), len(sample), time.Since(start))
+	return nil
+}
+
+// setQuantizer makes quantizer the current quantizer and appends it to the store
+func (dt *Data) setQuantizer(quantizer *Quantizer) error {
+	if dt.Store != nil {
+		dt.Store.Lock()
+		defer dt.Store.Unlock()
+	}
+	dt.Quantized.Lock()
+	dt.Quantized.Quantizer = quantizer
+	dt.Quantized.Unlock()
+	if dt.Store == nil {
+		return nil
+	}
+	record, err := quantizerRecord(quantizer)
+	if err != nil {
+		return err
+	}
+	return dt.Store.Append(record)
+}
+
+func quantizerRecord(quantizer *Quantizer) (*StoreRecord, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(quantizer)
+	if err != nil {
+		return nil, err
+	}
+	return &StoreRecord{
+		Op:    storeOpQuantizer,
+		Value: buf.Bytes(),
+	}, nil
+}
+
+func quantizerOfRecord(record *StoreRecord) (*Quantizer, error) {
+	quantizer := &Quantizer{}
+	err := gob.NewDecoder(bytes.NewReader(record.Value)).Decode(quantizer)
+	if err != nil {
+		return nil, err
+	}
+	return quantizer, nil
+}
@@ -0,0 +1,70 @@
+package data_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	data "github.com/bgokden/veri/data"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+func randomFeatures(random *rand.Rand, n int, dim int) [][]float32 {
+	features := make([][]float32, n)
+	for i := range features {
+		feature := make([]float32, dim)
+		for j := range feature {
+			feature[j] = random.Float32()
+		}
+		features[i] = feature
+	}
+	return features
+}
+
+func TestQuantizer(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	dim := 16
+	sample := randomFeatures(random, 1000, dim)
+	query := randomFeatures(random, 1, dim)[0]
+
+	pq, err := data.TrainQuantizer(&pb.DataConfig{Quantization: data.QuantizationPQ, PqSubspaces: 4, PqCentroids: 64}, sample)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(pq.Centroids))
+	assert.Equal(t, 64, pq.K)
+
+	sq, err := data.TrainQuantizer(&pb.DataConfig{Quantization: data.QuantizationSQ}, sample)
+	assert.Nil(t, err)
+	assert.Equal(t, dim, len(sq.Centroids))
+
+	for _, feature := range sample[:100] {
+		code := sq.Encode(feature)
+		assert.Equal(t, dim, len(code))
+		decoded := sq.Decode(code)
+		for d := range feature {
+			assert.True(t, math.Abs(float64(feature[d]-decoded[d])) <= float64(sq.Step[d])/2+1e-6)
+		}
+	}
+	assert.Nil(t, pq.Encode([]float32{0.1}))
+
+	// Asymmetric distance is the exact distance to the decoded feature
+	scoreFuncs := map[string]func(arr1 []float32, arr2 []float32) float64{
+		"VectorDistance":       data.VectorDistance,
+		"QuickVectorDistance":  data.QuickVectorDistance,
+		"VectorMultiplication": data.VectorMultiplication,
+		"CosineSimilarity":     data.CosineSimilarity,
+		"AngularDistance":      data.AngularDistance,
+	}
+	for _, quantizer := range []*data.Quantizer{pq, sq} {
+		for name, scoreFunc := range scoreFuncs {
+			table := quantizer.NewDistanceTable(query, name)
+			assert.NotNil(t, table)
+			for _, feature := range sample[:50] {
+				code := quantizer.Encode(feature)
+				assert.InDelta(t, scoreFunc(query, quantizer.Decode(code)), table.Score(code), 1e-3, name)
+			}
+		}
+		assert.Nil(t, quantizer.NewDistanceTable(query, "AnnoyAngularDistance"))
+	}
+}
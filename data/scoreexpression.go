@@ -0,0 +1,338 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bgokden/go-cache"
+	pb "github.com/bgokden/veri/veriservice"
+	"github.com/tidwall/gjson"
+)
+
+// ScoreExpression blends the vector score of a datum with numeric fields of its labels
+//
+// score is the vector score, fields are gjson paths prefixed by label or group as in filters
+// Operators are + - * / and parentheses, functions are log, log1p, exp, sqrt, abs, min and max
+// Missing or non numeric fields are 0
+//
+// Example: score - 0.1 * log1p(label.popularity) + 0.01 * label.age
+type ScoreExpression struct {
+	Expression string
+	root       scoreNode
+}
+
+type scoreNode interface {
+	eval(score float64, label []byte, groupLabel []byte) float64
+}
+
+// scoreExpressionCache keeps compiled expressions so that a query is compiled once on every node
+var scoreExpressionCache = cache.New(10*time.Minute, 1*time.Minute)
+
+var scoreFunctions = map[string]func(args []float64) float64{
+	"log":   func(args []float64) float64 { return math.Log(args[0]) },
+	"log1p": func(args []float64) float64 { return math.Log1p(args[0]) },
+	"exp":   func(args []float64) float64 { return math.Exp(args[0]) },
+	"sqrt":  func(args []float64) float64 { return math.Sqrt(args[0]) },
+	"abs":   func(args []float64) float64 { return math.Abs(args[0]) },
+	"min": func(args []float64) float64 {
+		result := args[0]
+		for _, arg := range args[1:] {
+			result = math.Min(result, arg)
+		}
+		return result
+	},
+	"max": func(args []float64) float64 {
+		result := args[0]
+		for _, arg := range args[1:] {
+			result = math.Max(result, arg)
+		}
+		return result
+	},
+}
+
+// GetScoreExpression returns the compiled score expression, nil for an empty expression
+func GetScoreExpression(expression string) (*ScoreExpression, error) {
+	if len(strings.TrimSpace(expression)) == 0 {
+		return nil, nil
+	}
+	if cached, ok := scoreExpressionCache.Get(expression); ok {
+		return cached.(*ScoreExpression), nil
+	}
+	scoreExpression, err := CompileScoreExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	scoreExpressionCache.Set(expression, scoreExpression, cache.DefaultExpiration)
+	return scoreExpression, nil
+}
+
+// getSearchScoreExpression returns the score expression of a search config
+// An invalid expression is rejected before the search, here the vector score is used
+func getSearchScoreExpression(config *pb.SearchConfig) *ScoreExpression {
+	scoreExpression, err := GetScoreExpression(config.GetScoreExpression())
+	if err != nil {
+		log.Printf("Score expression error: %v\n", err)
+		return nil
+	}
+	return scoreExpression
+}
+
+// CompileScoreExpression parses a score expression
+func CompileScoreExpression(expression string) (*ScoreExpression, error) {
+	tokens, err := tokenizeScoreExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &scoreParser{tokens: tokens}
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("Unexpected %v in score expression", p.tokens[p.pos])
+	}
+	return &ScoreExpression{
+		Expression: expression,
+		root:       root,
+	}, nil
+}
+
+// Score returns the blended score of datum, a nil expression returns the vector score
+// NaN and infinite results are the worst score of the direction so that they are ranked last
+func (s *ScoreExpression) Score(vectorScore float64, datum *pb.Datum, higherIsBetter bool) float64 {
+	if s == nil || s.root == nil {
+		return vectorScore
+	}
+	result := s.root.eval(vectorScore, datum.GetValue().GetLabel(), datum.GetKey().GetGroupLabel())
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		if higherIsBetter {
+			return -math.MaxFloat64
+		}
+		return math.MaxFloat64
+	}
+	return result
+}
+
+type scoreConstant float64
+
+func (c scoreConstant) eval(score float64, label []byte, groupLabel []byte) float64 {
+	return float64(c)
+}
+
+type scoreVector struct{}
+
+func (scoreVector) eval(score float64, label []byte, groupLabel []byte) float64 {
+	return score
+}
+
+type scoreField struct {
+	group bool
+	path  string
+}
+
+func (f scoreField) eval(score float64, label []byte, groupLabel []byte) float64 {
+	json := label
+	if f.group {
+		json = groupLabel
+	}
+	result := gjson.GetBytes(json, f.path)
+	if result.Type != gjson.Number {
+		return 0
+	}
+	return result.Num
+}
+
+type scoreOperation struct {
+	op          byte
+	left, right scoreNode
+}
+
+func (o scoreOperation) eval(score float64, label []byte, groupLabel []byte) float64 {
+	left := o.left.eval(score, label, groupLabel)
+	right := o.right.eval(score, label, groupLabel)
+	switch o.op {
+	case '+':
+		return left + right
+	case '-':
+		return left - right
+	case '*':
+		return left * right
+	case '/':
+		if right == 0 {
+			return 0
+		}
+		return left / right
+	}
+	return 0
+}
+
+type scoreCall struct {
+	function func(args []float64) float64
+	args     []scoreNode
+}
+
+func (c scoreCall) eval(score float64, label []byte, groupLabel []byte) float64 {
+	args := make([]float64, len(c.args))
+	for i, arg := range c.args {
+		args[i] = arg.eval(score, label, groupLabel)
+	}
+	return c.function(args)
+}
+
+func isScoreNameChar(ch byte) bool {
+	return ch == '_' || ch == '.' || ch == '#' || ch == '@' ||
+		(ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9')
+}
+
+func tokenizeScoreExpression(expression string) ([]string, error) {
+	tokens := make([]string, 0)
+	for i := 0; i < len(expression); {
+		ch := expression[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case strings.IndexByte("+-*/(),", ch) >= 0:
+			tokens = append(tokens, expression[i:i+1])
+			i++
+		case (ch >= '0' && ch <= '9') || ch == '.':
+			start := i
+			for i < len(expression) && ((expression[i] >= '0' && expression[i] <= '9') || expression[i] == '.' ||
+				expression[i] == 'e' || expression[i] == 'E' ||
+				((expression[i] == '-' || expression[i] == '+') && (expression[i-1] == 'e' || expression[i-1] == 'E'))) {
+				i++
+			}
+			tokens = append(tokens, expression[start:i])
+		case isScoreNameChar(ch):
+			start := i
+			for i < len(expression) && isScoreNameChar(expression[i]) {
+				i++
+			}
+			tokens = append(tokens, expression[start:i])
+		default:
+			return nil, fmt.Errorf("Unexpected character %q in score expression at %v", ch, i)
+		}
+	}
+	return tokens, nil
+}
+
+type scoreParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *scoreParser) accept(token string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos] == token {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *scoreParser) parseSum() (scoreNode, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := byte('+')
+		if !p.accept("+") {
+			if !p.accept("-") {
+				return left, nil
+			}
+			op = '-'
+		}
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = scoreOperation{op: op, left: left, right: right}
+	}
+}
+
+func (p *scoreParser) parseProduct() (scoreNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := byte('*')
+		if !p.accept("*") {
+			if !p.accept("/") {
+				return left, nil
+			}
+			op = '/'
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = scoreOperation{op: op, left: left, right: right}
+	}
+}
+
+func (p *scoreParser) parseUnary() (scoreNode, error) {
+	if p.accept("-") {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return scoreOperation{op: '-', left: scoreConstant(0), right: node}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *scoreParser) parsePrimary() (scoreNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, errors.New("Unexpected end of score expression")
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+	if token == "(" {
+		node, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, errors.New("Expected ) in score expression")
+		}
+		return node, nil
+	}
+	if (token[0] >= '0' && token[0] <= '9') || token[0] == '.' {
+		number, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid number %v in score expression", token)
+		}
+		return scoreConstant(number), nil
+	}
+	switch {
+	case token == "score":
+		return scoreVector{}, nil
+	case strings.HasPrefix(token, "label."):
+		return scoreField{path: token[len("label."):]}, nil
+	case strings.HasPrefix(token, "group."):
+		return scoreField{group: true, path: token[len("group."):]}, nil
+	}
+	function, ok := scoreFunctions[token]
+	if !ok || !p.accept("(") {
+		return nil, fmt.Errorf("Unknown name %v in score expression", token)
+	}
+	call := scoreCall{function: function}
+	for {
+		arg, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		if p.accept(")") {
+			return call, nil
+		}
+		if !p.accept(",") {
+			return nil, fmt.Errorf("Expected , or ) after arguments of %v in score expression", token)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package data_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"testing"
+
+	data "github.com/bgokden/veri/data"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+func TestScoreExpression(t *testing.T) {
+	datum := data.NewDatum([]float32{1}, 1, 0, 1, 0, []byte(`{"boost": 2}`), []byte(`{"popularity": 99, "name": "x"}`), 0)
+	cases := map[string]float64{
+		`score`:                                   0.5,
+		`score - 0.1 * log1p(label.popularity)`:   0.5 - 0.1*math.Log1p(99),
+		`-score + 2 * (group.boost - 1) / 4`:      0,
+		`max(score, label.missing, label.name)`:   0.5,
+		`min(score, 1e-2) + sqrt(abs(-16)) - 1.5`: 2.51,
+		`score / label.missing`:                   0,
+	}
+	for expression, expected := range cases {
+		scoreExpression, err := data.CompileScoreExpression(expression)
+		assert.Nil(t, err, expression)
+		assert.InDelta(t, expected, scoreExpression.Score(0.5, datum, false), 1e-9, expression)
+	}
+	// Invalid results are the worst score
+	for _, expression := range []string{`log(-score)`, `log(label.missing)`, `exp(1000)`} {
+		scoreExpression, err := data.CompileScoreExpression(expression)
+		assert.Nil(t, err, expression)
+		assert.Equal(t, math.MaxFloat64, scoreExpression.Score(0.5, datum, false), expression)
+		assert.Equal(t, -math.MaxFloat64, scoreExpression.Score(0.5, datum, true), expression)
+	}
+	for _, expression := range []string{`score +`, `popularity`, `log(score`, `unknown(1)`, `score $ 1`} {
+		_, err := data.CompileScoreExpression(expression)
+		assert.NotNil(t, err, expression)
+	}
+}
+
+func TestDataHybridSearch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	dt, err := data.NewData(&pb.DataConfig{Name: "hybrid", TargetN: 1000}, dir)
+	assert.Nil(t, err)
+	defer dt.Close()
+	for i := 0; i < 50; i++ {
+		popularity := 0
+		if i == 20 {
+			popularity = 100
+		}
+		label := []byte(fmt.Sprintf(`{"i": %v, "popularity": %v}`, i, popularity))
+		assert.Nil(t, dt.Insert(data.NewDatum([]float32{float32(i), 1}, 2, 0, 1, 0, []byte("{}"), label, 0), nil))
+	}
+	query := data.NewDatum([]float32{25, 1}, 2, 0, 1, 0, nil, nil, 0)
+	config := data.DefaultSearchConfig()
+	config.Limit = 3
+	config.ScoreExpression = `score - 0.1 * label.popularity`
+	collector := dt.Search(query, config)
+	assert.Equal(t, 3, len(collector.List))
+	assert.Equal(t, float32(20), collector.List[0].Datum.Key.Feature[0])
+	assert.InDelta(t, -5.0, collector.List[0].Score, 1e-6)
+	assert.InDelta(t, 5.0, collector.List[0].VectorScore, 1e-6)
+
+	// Merge rescoring gives the same order and scores
+	result, _, err := dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(result))
+	assert.Equal(t, float32(20), result[0].Datum.Key.Feature[0])
+	assert.InDelta(t, -5.0, result[0].Score, 1e-6)
+	assert.Equal(t, float32(25), result[1].Datum.Key.Feature[0])
+
+	config.ScoreExpression = `score -`
+	_, _, err = dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+	assert.NotNil(t, err)
+}
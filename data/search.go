@@ -3,10 +3,11 @@ package data
 import (
 	"encoding/json"
 	"errors"
-	"log"
-	"sort"
+	"fmt"
+	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jinzhu/copier"
@@ -24,6 +25,13 @@ import (
 // 	Duration       time.Duration                                `json:"-"`
 // }
 
+const (
+	// filterExpansionFactor is how much a filtered search grows the index candidates each round
+	filterExpansionFactor = 4
+	// filterScanFraction sets the default candidate budget of filtered searches to 1/filterScanFraction of the index
+	filterScanFraction = 4
+)
+
 func DefaultSearchConfig() *pb.SearchConfig {
 	return &pb.SearchConfig{
 		ScoreFuncName: "VectorDistance",
@@ -43,16 +51,19 @@ func EncodeSearchConfig(sc *pb.SearchConfig) []byte {
 	return marshalled
 }
 
-// Collector collects results
+// Collector collects the best N results in a TopK
 type Collector struct {
-	List           []*pb.ScoredDatum
-	ScoreFunc      func(arr1 []float32, arr2 []float32) float64
-	MaxScore       float64
-	DatumKey       *pb.DatumKey
-	N              uint32
-	HigherIsBetter bool
-	Filters        []string
-	GroupFilters   []string
+	TopK
+	ScoreFunc       func(arr1 []float32, arr2 []float32) float64
+	MaxScore        float64
+	DatumKey        *pb.DatumKey
+	Filters         []string
+	GroupFilters    []string
+	Filter          *Filter
+	ScoreExpression *ScoreExpression
+	RadiusSearch    bool
+	Radius          float64
+	Cursor          *Cursor
 }
 
 // // ScoredDatum helps to keep Data ordered
@@ -63,29 +74,36 @@ type Collector struct {
 
 // Insert add a new scored datum to collector
 func (c *Collector) Insert(scoredDatum *pb.ScoredDatum) error {
-	itemAdded := false
-	if uint32(len(c.List)) < c.N {
-		c.List = append(c.List, scoredDatum)
-		itemAdded = true
-	} else if (c.HigherIsBetter && scoredDatum.Score > c.List[len(c.List)-1].Score) ||
-		(!c.HigherIsBetter && scoredDatum.Score < c.List[len(c.List)-1].Score) {
-		c.List[len(c.List)-1] = scoredDatum
-		itemAdded = true
-	}
-	if itemAdded {
-		if c.HigherIsBetter {
-			sort.Slice(c.List, func(i, j int) bool {
-				return c.List[i].Score > c.List[j].Score
-			})
-		} else {
-			sort.Slice(c.List, func(i, j int) bool {
-				return c.List[i].Score < c.List[j].Score
-			})
-		}
+	if c.N == 0 {
+		return nil
+	}
+	if c.RadiusSearch && !WithinRadius(c.HigherIsBetter, c.Radius, scoredDatum.GetVectorScore()) {
+		return nil
+	}
+	if !c.Cursor.AfterDatum(scoredDatum, c.HigherIsBetter) {
+		return nil // returned on a previous page
 	}
+	c.Push(scoredDatum)
 	return nil
 }
 
+// Scored returns datum with the score of the score expression, the vector score is kept for merging
+func (c *Collector) Scored(datum *pb.Datum, vectorScore float64) *pb.ScoredDatum {
+	return &pb.ScoredDatum{
+		Datum:       datum,
+		Score:       c.ScoreExpression.Score(vectorScore, datum, c.HigherIsBetter),
+		VectorScore: vectorScore,
+	}
+}
+
+// WithinRadius is true if vectorScore is not worse than radius
+func WithinRadius(higherIsBetter bool, radius float64, vectorScore float64) bool {
+	if higherIsBetter {
+		return vectorScore >= radius
+	}
+	return vectorScore <= radius
+}
+
 // // Send collects the results
 // func (c *Collector) Send(buf *z.Buffer) error {
 // 	err := buf.SliceIterate(func(s []byte) error {
@@ -141,7 +159,15 @@ func (c *Collector) Insert(scoredDatum *pb.ScoredDatum) error {
 // 	return err
 // }
 
+// HasFilters is true if some datums may not pass the filters
+func (c *Collector) HasFilters() bool {
+	return len(c.Filters) > 0 || len(c.GroupFilters) > 0 || c.Filter != nil
+}
+
 func (c *Collector) PassesFilters(datum *pb.Datum) bool {
+	if c.Filter != nil && !c.Filter.Matches(datum) {
+		return false
+	}
 	if len(c.GroupFilters) > 0 {
 		jsonLabel := string(datum.Key.GroupLabel)
 		for _, filter := range c.GroupFilters {
@@ -235,14 +261,16 @@ func (c *Collector) PassesFilters(datum *pb.Datum) bool {
 // }
 
 var vectorComparisonFuncs = map[string]func(arr1 []float32, arr2 []float32) float64{
-	"AnnoyVectorDistance":   VectorDistance,
-	"AnnoyCosineSimilarity": CosineSimilarity,
-	"VectorDistance":        VectorDistance,
-	"VectorMultiplication":  VectorMultiplication,
-	"CosineSimilarity":      CosineSimilarity,
-	"QuickVectorDistance":   QuickVectorDistance,
-	"AngularDistance":       AngularDistance,
-	"AnnoyAngularDistance":  AngularDistance,
+	"AnnoyVectorDistance":       VectorDistance,
+	"AnnoyCosineSimilarity":     CosineSimilarity,
+	"VectorDistance":            VectorDistance,
+	"VectorMultiplication":      VectorMultiplication,
+	"CosineSimilarity":          CosineSimilarity,
+	"QuickVectorDistance":       QuickVectorDistance,
+	"AngularDistance":           AngularDistance,
+	"AnnoyAngularDistance":      AngularDistance,
+	"AnnoyQuickVectorDistance":  QuickVectorDistance,
+	"AnnoyVectorMultiplication": VectorMultiplication,
 }
 
 func GetVectorComparisonFunction(name string) func(arr1 []float32, arr2 []float32) float64 {
@@ -252,62 +280,168 @@ func GetVectorComparisonFunction(name string) func(arr1 []float32, arr2 []float3
 	return VectorDistance
 }
 
-// // Search does a search based on distances of keys
-// func (dt *Data) Search(datum *pb.Datum, config *pb.SearchConfig) *Collector {
-// 	// log.Printf("Search: %v\n", datum)
-// 	if config == nil {
-// 		config = DefaultSearchConfig()
-// 	}
-// 	c := &Collector{}
-// 	c.List = make([]*pb.ScoredDatum, 0, config.Limit)
-// 	c.DatumKey = datum.Key
-// 	c.ScoreFunc = GetVectorComparisonFunction(config.ScoreFuncName)
-// 	c.HigherIsBetter = config.HigherIsBetter
-// 	c.N = config.Limit
-// 	c.Filters = config.Filters
-// 	c.GroupFilters = config.GroupFilters
-// 	stream := dt.DB.NewStream()
-// 	// db.NewStreamAt(readTs) for managed mode.
-
-// 	// -- Optional settings
-// 	stream.NumGo = 16                     // Set number of goroutines to use for iteration.
-// 	stream.Prefix = nil                   // Leave nil for iteration over the whole DB.
-// 	stream.LogPrefix = "Badger.Streaming" // For identifying stream logs. Outputs to Logger.
-
-// 	// ChooseKey is called concurrently for every key. If left nil, assumes true by default.
-// 	stream.ChooseKey = nil
-
-// 	// KeyToList is called concurrently for chosen keys. This can be used to convert
-// 	// Badger data into custom key-values. If nil, uses stream.ToList, a default
-// 	// implementation, which picks all valid key-values.
-// 	stream.KeyToList = c.ToList // nil
-
-// 	// -- End of optional settings.
-
-// 	// Send is called serially, while Stream.Orchestrate is running.
-
-// 	stream.Send = c.Send
-
-// 	// Run the stream
-// 	if err := stream.Orchestrate(context.Background()); err != nil {
-// 		return nil
-// 	}
-// 	// Done.
-// 	return c
-// }
+// NewCollector creates a collector for a search of datum with config
+func NewCollector(datum *pb.Datum, config *pb.SearchConfig) *Collector {
+	c := &Collector{TopK: NewTopK(config.Limit, config.HigherIsBetter)}
+	c.TieBreak = config.Deterministic
+	c.DatumKey = datum.Key
+	c.ScoreFunc = GetVectorComparisonFunction(config.ScoreFuncName)
+	c.Filters = config.Filters
+	c.GroupFilters = config.GroupFilters
+	c.Filter = getSearchFilter(config)
+	c.ScoreExpression = getSearchScoreExpression(config)
+	c.RadiusSearch = config.RadiusSearch
+	c.Radius = config.Radius
+	c.Cursor = getSearchCursor(config)
+	return c
+}
+
+// Search does an exact search by scoring every entry with the score function of config
+// With Quantized in config, quantized entries are scored by their codes and only the best candidates exactly
+// The list of the collector is sorted from the best
+func (dt *Data) Search(datum *pb.Datum, config *pb.SearchConfig) *Collector {
+	c := dt.search(datum, config)
+	c.Sort()
+	return c
+}
+
+// search scores entries in parallel, each worker collects its own top list which are merged at the end
+func (dt *Data) search(datum *pb.Datum, config *pb.SearchConfig) *Collector {
+	if config == nil {
+		config = DefaultSearchConfig()
+	}
+	c := NewCollector(datum, config)
+	if c.N == 0 {
+		return c
+	}
+	if entries, ok := dt.filterCandidates(c.Filter); ok {
+		return dt.searchCandidates(datum, config, entries)
+	}
+	if config.Quantized && !c.RadiusSearch {
+		// Approximate scores of the quantized search may drop datums at the radius
+		if quantizedCollector := dt.searchQuantized(datum, config); quantizedCollector != nil {
+			return quantizedCollector
+		}
+	}
+	collectors := dt.scanDBMap(datum, config, c.N, func(workerCollector *Collector, entry *DBMapEntry) {
+		datumE, err := entry.Datum()
+		if err != nil || !workerCollector.PassesFilters(datumE) {
+			return
+		}
+		workerCollector.Insert(workerCollector.Scored(datumE, workerCollector.ScoreFunc(datum.Key.Feature, datumE.Key.Feature)))
+	})
+	for _, workerCollector := range collectors {
+		for _, scoredDatum := range workerCollector.List {
+			c.Insert(scoredDatum)
+		}
+	}
+	return c
+}
+
+// scanDBMap runs entryFunction on every entry in parallel
+// Each worker has its own collector of size n which are returned to be merged
+func (dt *Data) scanDBMap(datum *pb.Datum, config *pb.SearchConfig, n uint32, entryFunction func(workerCollector *Collector, entry *DBMapEntry)) []*Collector {
+	numberOfWorkers := runtime.NumCPU()
+	entryStream := make(chan *DBMapEntry, numberOfWorkers*16)
+	collectors := make([]*Collector, numberOfWorkers)
+	var workerWaitGroup sync.WaitGroup
+	for w := 0; w < numberOfWorkers; w++ {
+		workerCollector := NewCollector(datum, config)
+		workerCollector.N = n
+		collectors[w] = workerCollector
+		workerWaitGroup.Add(1)
+		go func() {
+			defer workerWaitGroup.Done()
+			for entry := range entryStream {
+				entryFunction(workerCollector, entry)
+			}
+		}()
+	}
+	dt.LoopDBMap(func(entry *DBMapEntry) error {
+		entryStream <- entry
+		return nil
+	})
+	close(entryStream)
+	workerWaitGroup.Wait()
+	return collectors
+}
+
+// searchQuantized scores entries by their codes and re-ranks the best candidates with full precision
+// Only candidates that can enter a worker list are decoded, entries without a code of the current quantizer are scored exactly
+// Codes only candidates are re-ranked with their decoded features
+// Candidates before the cursor are skipped by their exact scores
+// It returns nil if there is no quantizer or the score function can not be approximated
+func (dt *Data) searchQuantized(datum *pb.Datum, config *pb.SearchConfig) *Collector {
+	quantizer := dt.getQuantizer()
+	if quantizer == nil {
+		return nil
+	}
+	table := quantizer.NewDistanceTable(datum.Key.Feature, config.ScoreFuncName)
+	if table == nil {
+		return nil
+	}
+	rerankFactor := config.RerankFactor
+	if rerankFactor == 0 {
+		rerankFactor = quantizedRerankFactor
+	}
+	// The cursor is of exact scores, worker lists are ranked by approximate scores without it
+	cursor := getSearchCursor(config)
+	var candidateConfig pb.SearchConfig
+	copier.Copy(&candidateConfig, config)
+	candidateConfig.PageToken = ""
+	afterCursor := func(workerCollector *Collector, datumE *pb.Datum) bool {
+		if cursor == nil {
+			return true
+		}
+		exact := workerCollector.Scored(datumE, workerCollector.ScoreFunc(datum.Key.Feature, datumE.Key.Feature))
+		return cursor.AfterDatum(exact, config.HigherIsBetter)
+	}
+	collectors := dt.scanDBMap(datum, &candidateConfig, config.Limit*rerankFactor, func(workerCollector *Collector, entry *DBMapEntry) {
+		if entry.Quantizer != quantizer {
+			datumE, err := entry.Datum()
+			if err != nil || !workerCollector.PassesFilters(datumE) || !afterCursor(workerCollector, datumE) {
+				return
+			}
+			workerCollector.Insert(workerCollector.Scored(datumE, workerCollector.ScoreFunc(datum.Key.Feature, datumE.Key.Feature)))
+			return
+		}
+		score := table.Score(entry.Code)
+		if workerCollector.ScoreExpression == nil && !workerCollector.Accepts(score) {
+			return
+		}
+		// Codes only entries are decoded with their features
+		datumE, err := entry.Datum()
+		if err != nil || !workerCollector.PassesFilters(datumE) {
+			return
+		}
+		if !afterCursor(workerCollector, datumE) {
+			return
+		}
+		workerCollector.Insert(workerCollector.Scored(datumE, score))
+	})
+	c := NewCollector(datum, config)
+	for _, workerCollector := range collectors {
+		for _, scoredDatum := range workerCollector.List {
+			// Approximate score is replaced, it is the same for decoded features
+			c.Insert(c.Scored(scoredDatum.Datum, c.ScoreFunc(datum.Key.Feature, scoredDatum.Datum.Key.Feature)))
+		}
+	}
+	return c
+}
 
 // StreamSearch does a search based on distances of keys
-func (dt *Data) StreamSearch(datum *pb.Datum, scoredDatumStream chan<- *pb.ScoredDatum, queryWaitGroup *sync.WaitGroup, config *pb.SearchConfig) error {
+// Context is applied at the merge, more datums are streamed if it excludes or re-ranks datums
+func (dt *Data) StreamSearch(datum *pb.Datum, scoredDatumStream chan<- *pb.ScoredDatum, queryWaitGroup *sync.WaitGroup, config *pb.SearchConfig, context *pb.SearchContext) error {
 	var collector *Collector
 	defer queryWaitGroup.Done()
 	if config == nil {
 		config = DefaultSearchConfig()
 	}
+	config = contextSearchConfig(config, context)
 	if strings.HasPrefix(config.ScoreFuncName, "Annoy") {
-		collector = dt.SearchAnnoy(datum, config)
+		collector = dt.searchAnnoy(datum, config)
 	} else {
-		return errors.New("Base search is not working yet")
-		// collector = dt.Search(datum, config)
+		collector = dt.search(datum, config)
 	}
 	if collector != nil {
 		for _, i := range collector.List {
@@ -318,9 +452,9 @@ func (dt *Data) StreamSearch(datum *pb.Datum, scoredDatumStream chan<- *pb.Score
 	return nil
 }
 
-func GetSearchKey(datum *pb.Datum, config *pb.SearchConfig) string {
+func GetSearchKey(datum *pb.Datum, config *pb.SearchConfig, context *pb.SearchContext) string {
 	keyByte, err := GetKeyAsBytes(datum)
-	signature := EncodeSearchConfig(config)
+	signature := append(EncodeSearchConfig(config), EncodeSearchContext(context)...)
 	if err != nil {
 		return util.EncodeToString(signature)
 	}
@@ -328,18 +462,37 @@ func GetSearchKey(datum *pb.Datum, config *pb.SearchConfig) string {
 }
 
 // AggregatedSearch searches and merges other resources
-func (dt *Data) AggregatedSearch(datum *pb.Datum, scoredDatumStreamOutput chan<- *pb.ScoredDatum, upperWaitGroup *sync.WaitGroup, config *pb.SearchConfig) error {
+// stats is optional and collects which sources answered before timeout
+// context is passed to the sources and applied to the merge
+// config is not modified, sources get a copy with a shorter timeout
+func (dt *Data) AggregatedSearch(datum *pb.Datum, scoredDatumStreamOutput chan<- *pb.ScoredDatum, upperWaitGroup *sync.WaitGroup, config *pb.SearchConfig, context *pb.SearchContext, stats *SearchStats) error {
 	duration := time.Duration(config.Timeout) * time.Millisecond
 	timeLimit := time.After(duration)
-	queryKey := GetSearchKey(datum, config)
-	config.Timeout = uint64(float64(config.Timeout) * 0.9) // Decrase timeout for downstream
+	queryKey := GetSearchKey(datum, config, context)
+	var downstreamConfig pb.SearchConfig
+	copier.Copy(&downstreamConfig, config)
+	downstreamConfig.Timeout = uint64(float64(config.Timeout) * 0.9) // Decrase timeout for downstream
+	config = &downstreamConfig
 	if dt.QueryCache == nil {
-		dt.InitData()
+		err := dt.InitData()
+		if err != nil {
+			if upperWaitGroup != nil {
+				upperWaitGroup.Done()
+			}
+			return err
+		}
 	}
-	if config.CacheDuration > 0 {
+	// Deterministic searches always ask every source
+	useCache := config.CacheDuration > 0 && !config.Deterministic
+	if useCache {
 		if result, ok := dt.QueryCache.Get(queryKey); ok {
-			cachedResult := result.([]*pb.ScoredDatum)
-			resultCopy := CloneResult(cachedResult)
+			cached := result.(*cachedSearch)
+			if stats != nil {
+				for _, sourceID := range cached.SourceIDs {
+					stats.Add(sourceID, true)
+				}
+			}
+			resultCopy := CloneResult(cached.Result)
 			for _, i := range resultCopy {
 				scoredDatumStreamOutput <- i
 			}
@@ -358,20 +511,38 @@ func (dt *Data) AggregatedSearch(datum *pb.Datum, scoredDatumStreamOutput chan<-
 	// internal
 	queryWaitGroup.Add(1)
 	go func() {
-		dt.StreamSearch(datum, scoredDatumStream, &queryWaitGroup, config)
+		dt.StreamSearch(datum, scoredDatumStream, &queryWaitGroup, config, context)
 	}()
 	// external
-	dt.RunOnRandomSources(5, func(source DataSource) error {
+	// Results of sources are merged again, diversification and ResultLimit are applied once at the root
+	sourceConfig := sourceSearchConfig(config)
+	sourceIDs := make([]string, 0)
+	answered := make([]*uint32, 0)
+	timedOut := false
+	querySource := func(source DataSource) error {
 		queryWaitGroup.Add(1)
-		go source.StreamSearch(datum, scoredDatumStream, &queryWaitGroup, config)
+		sourceAnswered := new(uint32)
+		sourceIDs = append(sourceIDs, source.GetID())
+		answered = append(answered, sourceAnswered)
+		go func() {
+			err := source.StreamSearch(datum, scoredDatumStream, &queryWaitGroup, sourceConfig, context)
+			if err == nil {
+				atomic.StoreUint32(sourceAnswered, 1)
+			}
+		}()
 		return nil
-	})
+	}
+	if config.Deterministic {
+		dt.RunOnAllSources(querySource)
+	} else {
+		dt.RunOnRandomSources(5, querySource)
+	}
 	go func() {
 		defer close(waitChannel)
 		queryWaitGroup.Wait()
 	}()
 	// stream merge
-	temp := NewAggrator(config, false, nil)
+	temp := NewAggrator(config, false, context)
 	dataAvailable := true
 	for dataAvailable {
 		select {
@@ -387,10 +558,22 @@ func (dt *Data) AggregatedSearch(datum *pb.Datum, scoredDatumStreamOutput chan<-
 			break
 		case <-timeLimit:
 			// log.Printf("timeout")
+			timedOut = true
 			dataAvailable = false
 			break
 		}
 	}
+	complete := !timedOut
+	for i, sourceID := range sourceIDs {
+		sourceAnswered := atomic.LoadUint32(answered[i]) == 1
+		complete = complete && sourceAnswered
+		if stats != nil {
+			stats.Add(sourceID, sourceAnswered)
+		}
+	}
+	if stats != nil && timedOut {
+		stats.SetTimedOut()
+	}
 	// log.Printf("search collected data\n")
 	// Search End
 	result := temp.Result()
@@ -401,14 +584,68 @@ func (dt *Data) AggregatedSearch(datum *pb.Datum, scoredDatumStreamOutput chan<-
 	if upperWaitGroup != nil {
 		upperWaitGroup.Done()
 	}
-	if config.CacheDuration > 0 {
+	// Partial results are not cached, a later search may get every answer
+	if useCache && complete {
 		cacheDuration := time.Duration(config.CacheDuration) * time.Second
-		dt.QueryCache.Set(queryKey, resultCopy, cacheDuration)
+		dt.QueryCache.Set(queryKey, &cachedSearch{Result: resultCopy, SourceIDs: sourceIDs}, cacheDuration)
 		// log.Printf("AggregatedSearch: finished. Set Cache Duration: %v\n", cacheDuration)
 	}
 	return nil
 }
 
+// cachedSearch is a complete result of AggregatedSearch with the ids of the sources that answered it
+type cachedSearch struct {
+	Result    []*pb.ScoredDatum
+	SourceIDs []string
+}
+
+// SearchStats collects how complete a distributed search is
+// Sources are counted once however many query datums they are asked for
+type SearchStats struct {
+	sync.Mutex
+	sources  map[string]bool // true if the source answered every query
+	timedOut bool
+}
+
+// Add records a query to the source with sourceID and whether it answered before timeout
+func (s *SearchStats) Add(sourceID string, answered bool) {
+	s.Lock()
+	defer s.Unlock()
+	if s.sources == nil {
+		s.sources = make(map[string]bool)
+	}
+	if previous, ok := s.sources[sourceID]; ok {
+		answered = answered && previous
+	}
+	s.sources[sourceID] = answered
+}
+
+// SetTimedOut records that the search timed out
+func (s *SearchStats) SetTimedOut() {
+	s.Lock()
+	defer s.Unlock()
+	s.timedOut = true
+}
+
+// Metadata returns a snapshot of stats, late answers are not included
+// A source is answered if it answered every query datum
+// The result is consistent if every queried source answered before the timeout
+func (s *SearchStats) Metadata() *pb.SearchMetadata {
+	s.Lock()
+	defer s.Unlock()
+	metadata := &pb.SearchMetadata{
+		SourcesQueried: uint32(len(s.sources)),
+		TimedOut:       s.timedOut,
+	}
+	for _, answered := range s.sources {
+		if answered {
+			metadata.SourcesAnswered++
+		}
+	}
+	metadata.Consistent = !metadata.TimedOut && metadata.SourcesAnswered == metadata.SourcesQueried
+	return metadata
+}
+
 func CloneResult(result []*pb.ScoredDatum) []*pb.ScoredDatum {
 	resultCopy := make([]*pb.ScoredDatum, len(result))
 	for i, sd := range result {
@@ -419,18 +656,89 @@ func CloneResult(result []*pb.ScoredDatum) []*pb.ScoredDatum {
 	return resultCopy
 }
 
+// datumSearchConfig returns a copy of config for the search of the i-th of n query datums
+// Sub-queries of a multi datum search get their own query id, peers drop repeated ids as loops
+func datumSearchConfig(config *pb.SearchConfig, i, n int) *pb.SearchConfig {
+	var datumConfig pb.SearchConfig
+	copier.Copy(&datumConfig, config)
+	if n > 1 && config.Uuid != "" {
+		datumConfig.Uuid = fmt.Sprintf("%v-%v", config.Uuid, i)
+	}
+	return &datumConfig
+}
+
+// sourceSearchConfig returns the config of sources without diversification and ResultLimit
+// Nodes serving a source return every candidate up to Limit for the merge of the caller
+func sourceSearchConfig(config *pb.SearchConfig) *pb.SearchConfig {
+	if !IsDiversified(config) && config.ResultLimit == 0 {
+		return config
+	}
+	var sourceConfig pb.SearchConfig
+	copier.Copy(&sourceConfig, config)
+	sourceConfig.MmrLambda = 0
+	sourceConfig.MaxPerGroup = 0
+	sourceConfig.ResultLimit = 0
+	return &sourceConfig
+}
+
+// pageSearchConfig returns the config searching sources for the results up to the end of the page of config
+// Offset is applied once after the merge so sources get none
+func pageSearchConfig(config *pb.SearchConfig) *pb.SearchConfig {
+	if config.Offset == 0 {
+		return config
+	}
+	var pageConfig pb.SearchConfig
+	copier.Copy(&pageConfig, config)
+	pageConfig.Limit = config.Limit + uint32(config.Offset)
+	if config.ResultLimit > 0 {
+		pageConfig.ResultLimit = config.ResultLimit + config.Offset
+	}
+	pageConfig.Offset = 0
+	return &pageConfig
+}
+
 // MultiAggregatedSearch searches and merges other resources
-func (dt *Data) MultiAggregatedSearch(datumList []*pb.Datum, config *pb.SearchConfig, context *pb.SearchContext) ([]*pb.ScoredDatum, error) {
+// Results before Offset of config are skipped, Limit is the page size
+// Results are continued after the PageToken of config, the token of the next page is in the metadata
+// A deterministic search queries every source and fails unless all of them answer before the timeout
+func (dt *Data) MultiAggregatedSearch(datumList []*pb.Datum, config *pb.SearchConfig, context *pb.SearchContext) ([]*pb.ScoredDatum, *pb.SearchMetadata, error) {
+	if _, err := GetFilter(config.GetFilter()); err != nil {
+		return nil, nil, err
+	}
+	if _, err := GetScoreExpression(config.GetScoreExpression()); err != nil {
+		return nil, nil, err
+	}
+	cursor, err := ParseCursor(config.GetPageToken())
+	if err != nil {
+		return nil, nil, err
+	}
+	if cursor != nil && IsDiversified(config) {
+		// Datums dropped by diversification are before the cursor of the page
+		return nil, nil, errors.New("Page token is not supported with diversification")
+	}
+	if cursor != nil && config.GroupLimit > 0 {
+		return nil, nil, errors.New("Page token is not supported in grouped search")
+	}
+	if cursor != nil && len(context.GetNegativeDatum()) > 0 {
+		return nil, nil, errors.New("Page token is not supported with negative datums")
+	}
+	if cursor != nil && context.GetPrioritize() && len(context.GetDatum()) > 0 {
+		// Sources rank by the search score which a prioritized context replaces
+		return nil, nil, errors.New("Page token is not supported with a prioritized context")
+	}
+	offset := config.Offset
+	config = pageSearchConfig(config)
 	duration := time.Duration(config.Timeout) * time.Millisecond
 	timeLimit := time.After(duration)
+	stats := &SearchStats{}
 	// Search Start
 	scoredDatumStream := make(chan *pb.ScoredDatum, 100)
 	var queryWaitGroup sync.WaitGroup
 	waitChannel := make(chan struct{})
 	// loop datumList
-	for _, datum := range datumList {
+	for i, datum := range datumList {
 		queryWaitGroup.Add(1)
-		go dt.AggregatedSearch(datum, scoredDatumStream, &queryWaitGroup, config)
+		go dt.AggregatedSearch(datum, scoredDatumStream, &queryWaitGroup, datumSearchConfig(config, i, len(datumList)), context, stats)
 	}
 	go func() {
 		defer close(waitChannel)
@@ -441,7 +749,7 @@ func (dt *Data) MultiAggregatedSearch(datumList []*pb.Datum, config *pb.SearchCo
 	if config.GroupLimit > 0 {
 		isGrouped = true
 	}
-	temp := NewAggrator(config, isGrouped, context)
+	temp := NewRootAggrator(config, isGrouped, context)
 	dataAvailable := true
 	for dataAvailable {
 		select {
@@ -457,71 +765,149 @@ func (dt *Data) MultiAggregatedSearch(datumList []*pb.Datum, config *pb.SearchCo
 			break
 		case <-timeLimit:
 			// log.Printf("timeout")
+			stats.SetTimedOut()
 			dataAvailable = false
 			break
 		}
 	}
 	// Search End
 	// log.Printf("MultiAggregatedSearch: finished")
-	return temp.Result(), nil
+	result := temp.Result()
+	if uint64(len(result)) <= offset {
+		result = []*pb.ScoredDatum{}
+	} else {
+		result = result[offset:]
+	}
+	metadata := stats.Metadata()
+	if config.Deterministic && !metadata.Consistent {
+		return nil, metadata, fmt.Errorf("Deterministic search is incomplete, %v of %v sources answered, timed out: %v", metadata.SourcesAnswered, metadata.SourcesQueried, metadata.TimedOut)
+	}
+	if len(result) > 0 && !IsDiversified(config) {
+		metadata.NextPageToken = NextCursor(result, cursor).Token()
+	}
+	return result, metadata, nil
 }
 
-// Search does a search based on distances of keys
+// SearchAnnoy does an approximate search with the index
+// The list of the collector is sorted from the best
 func (dt *Data) SearchAnnoy(datum *pb.Datum, config *pb.SearchConfig) *Collector {
+	c := dt.searchAnnoy(datum, config)
+	c.Sort()
+	return c
+}
+
+// searchAnnoy fetches index candidates
+// Filtered and radius searches fetch more candidates until Limit datums pass the filters and the radius,
+// when the candidate budget is exhausted an exact scan is done instead
+// Score functions without an index of their metric are searched exactly
+func (dt *Data) searchAnnoy(datum *pb.Datum, config *pb.SearchConfig) *Collector {
 	if config == nil {
 		config = DefaultSearchConfig()
 	}
-	c := &Collector{}
-	c.List = make([]*pb.ScoredDatum, 0, config.Limit)
-	c.DatumKey = datum.Key
-	c.ScoreFunc = GetVectorComparisonFunction(config.ScoreFuncName)
-	c.HigherIsBetter = config.HigherIsBetter
-	c.N = config.Limit
-	c.Filters = config.Filters
-	c.GroupFilters = config.GroupFilters
+	c := NewCollector(datum, config)
 	// features32 := make([]float32, len(datum.Key.Feature))
 	// for i, f := range datum.Key.Feature {
 	// 	features32[i] = float32(f)
 	// }
-	if dt.Annoyer.AnnoyIndex != nil && dt.Annoyer.DataIndex != nil && len(*(dt.Annoyer.DataIndex)) > 0 {
-		dt.Annoyer.RLock()
-		var result []int
-		var distances []float32
-		index := *(dt.Annoyer.DataIndex)
-		dt.Annoyer.AnnoyIndex.GetNnsByVector(datum.Key.Feature, len(index), int(config.Limit), &result, &distances)
-		if result != nil {
-			counter := uint32(0)
-			for i := 0; i < len(result); i++ {
-				datumEntry := index[result[i]]
-				if datumEntry != nil {
-					datumKey, _ := ToDatumKey(*(datumEntry.Key))
-					datumValue, _ := ToDatumValue(*(datumEntry.Value))
-					datumE := &pb.Datum{
-						Key:   datumKey,
-						Value: datumValue,
-					}
-					if datumE != nil && c.PassesFilters(datumE) {
-						scoredDatum := &pb.ScoredDatum{
-							Datum: datumE,
-							Score: c.ScoreFunc(datum.Key.Feature, datumE.Key.Feature),
-						}
-						// log.Printf("Result %v d: %v\n", result[i], distances[i])
-						c.List = append(c.List, scoredDatum)
-						counter += 1
-						if counter >= c.N {
-							break
-						}
-					} else {
-						log.Printf("Datum E is nil. %v d: %v\n", result[i], distances[i])
-					}
-				}
-			}
-		}
+	dt.Annoyer.RLock()
+	index := dt.Annoyer.Index
+	if index == nil || index.Len() == 0 {
 		dt.Annoyer.RUnlock()
-	} else {
-		log.Println("Fallback to regular search")
-		return c // Fallback is emptry list now
-		//return dt.Search(datum, config)
+		// Index is not built yet, exact search covers small and fresh data
+		return dt.search(datum, config)
+	}
+	if !index.Serves(config.ScoreFuncName) {
+		dt.Annoyer.RUnlock()
+		// Neighbours of another metric are not the best candidates of the score function
+		return dt.search(datum, config)
+	}
+	hasDelta := !dt.Delta.IsEmpty()
+	candidateCount := int(config.Limit)
+	if hasDelta {
+		candidateCount += dt.Delta.Len() // masked candidates are dropped after the search
+	}
+	if c.ScoreExpression != nil {
+		// Score expression may reorder candidates of the vector score
+		candidateCount *= filterExpansionFactor
+	}
+	budget := candidateCount
+	if c.RadiusSearch || c.Cursor != nil {
+		// candidates outside the radius or on previous pages are dropped after the search
+		budget = GetCandidateBudget(config, index.Len())
+	}
+	if c.HasFilters() {
+		// filtered candidates are dropped after the search
+		candidateCount *= filterExpansionFactor
+		budget = GetCandidateBudget(config, index.Len())
+		if entries, ok := dt.filterCandidates(c.Filter); ok && len(entries) <= budget {
+			dt.Annoyer.RUnlock()
+			// Field index selects few enough datums to score them exactly
+			return dt.searchCandidates(datum, config, entries)
+		}
+	}
+	for {
+		candidateCount = min(candidateCount, max(budget, int(config.Limit)))
+		c = NewCollector(datum, config)
+		exhausted := dt.searchIndexCandidates(c, index, datum, candidateCount, config, hasDelta)
+		if uint32(len(c.List)) >= c.N || exhausted || !(c.HasFilters() || c.RadiusSearch || c.Cursor != nil) {
+			break
+		}
+		if candidateCount >= budget {
+			dt.Annoyer.RUnlock()
+			// Index candidates are not enough, exact scan finds every passing datum
+			return dt.search(datum, config)
+		}
+		candidateCount *= filterExpansionFactor
 	}
+	dt.Annoyer.RUnlock()
 	return c
 }
+
+// GetCandidateBudget returns how many index candidates a filtered search fetches at most
+// CandidateBudget of config has priority, the default is a quarter of the index
+func GetCandidateBudget(config *pb.SearchConfig, nItems int) int {
+	if config.GetCandidateBudget() > 0 {
+		return int(config.GetCandidateBudget())
+	}
+	return max(int(config.Limit), nItems/filterScanFraction)
+}
+
+// searchIndexCandidates collects candidateCount index results and delta entries passing the filters
+// It returns true if candidateCount covers every live entry of the index, or no more candidates are within the radius
+// Fewer results than candidateCount do not exhaust the index, approximate searches may miss reachable entries
+func (dt *Data) searchIndexCandidates(c *Collector, index Index, datum *pb.Datum, candidateCount int, config *pb.SearchConfig, hasDelta bool) bool {
+	result := index.Search(datum.Key.Feature, candidateCount, config)
+	now := time.Now().Unix()
+	outOfRadius := false
+	if c.RadiusSearch && len(result) > 0 {
+		// Candidates are ordered by distance, the farthest one tells if more are within the radius
+		farthest, err := result[len(result)-1].DatumKey()
+		outOfRadius = err == nil && !WithinRadius(c.HigherIsBetter, c.Radius, c.ScoreFunc(datum.Key.Feature, farthest.Feature))
+	}
+	for _, datumEntry := range result {
+		if datumEntry.ExprireAt != 0 && datumEntry.ExprireAt <= now {
+			continue
+		}
+		if hasDelta && dt.Delta.IsMasked(datumEntry.MapKey()) {
+			// Deleted or re-inserted since the build, delta has the current state
+			continue
+		}
+		datumE, err := datumEntry.Datum()
+		if err == nil && c.PassesFilters(datumE) {
+			c.Insert(c.Scored(datumE, c.ScoreFunc(datum.Key.Feature, datumE.Key.Feature)))
+			if uint32(len(c.List)) >= c.N && c.ScoreExpression == nil {
+				break
+			}
+		}
+	}
+	if hasDelta {
+		dt.Delta.LoopEntries(func(entry *DBMapEntry) error {
+			datumE, err := entry.Datum()
+			if err == nil && c.PassesFilters(datumE) {
+				c.Insert(c.Scored(datumE, c.ScoreFunc(datum.Key.Feature, datumE.Key.Feature)))
+			}
+			return nil
+		})
+	}
+	return outOfRadius || candidateCount >= index.Len()
+}
@@ -0,0 +1,319 @@
+package data_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+
+	data "github.com/bgokden/veri/data"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+func TestDataRadiusSearch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	dt, err := data.NewData(&pb.DataConfig{Name: "radius", TargetN: 10000, IndexMetrics: []string{data.AnnoyMetricEuclidean}}, dir)
+	assert.Nil(t, err)
+	defer dt.Close()
+	for i := 0; i < 1000; i++ {
+		assert.Nil(t, dt.Insert(data.NewDatum([]float32{float32(i), 1}, 2, 0, 1, 0, []byte("{}"), []byte("{}"), 0), nil))
+	}
+	dt.Process(true)
+	query := data.NewDatum([]float32{500.25, 1}, 2, 0, 1, 0, nil, nil, 0) // no ties in scores
+	config := data.DefaultSearchConfig()
+	config.RadiusSearch = true
+	config.Radius = 10.5
+	config.Limit = 100
+
+	// Every datum within the radius is found, the index is expanded when needed
+	for _, scoreFuncName := range []string{"VectorDistance", "AnnoyVectorDistance"} {
+		config.ScoreFuncName = scoreFuncName
+		config.Limit = 100
+		collector := dt.SearchAnnoy(query, config)
+		assert.Equal(t, 21, len(collector.List), scoreFuncName)
+		for _, scoredDatum := range collector.List {
+			assert.LessOrEqual(t, scoredDatum.Score, 10.5)
+		}
+		config.Limit = 5
+		collector = dt.SearchAnnoy(query, config)
+		assert.Equal(t, 5, len(collector.List), scoreFuncName)
+	}
+
+	// Pages of the merged result do not overlap
+	config.ScoreFuncName = "VectorDistance"
+	config.Limit = 8
+	seen := make(map[float32]bool)
+	for page := 0; page < 3; page++ {
+		config.Offset = uint64(page) * 8
+		result, _, err := dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{8, 8, 5}[page], len(result))
+		for _, scoredDatum := range result {
+			assert.False(t, seen[scoredDatum.Datum.Key.Feature[0]])
+			seen[scoredDatum.Datum.Key.Feature[0]] = true
+		}
+	}
+	assert.Equal(t, 21, len(seen))
+}
+
+func TestDataSearchPageToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	dt, err := data.NewData(&pb.DataConfig{Name: "pages", TargetN: 1000}, dir)
+	assert.Nil(t, err)
+	defer dt.Close()
+	for i := 0; i < 30; i++ {
+		// every score is shared by three datums
+		group := []byte(fmt.Sprintf(`{"i": %v}`, i))
+		assert.Nil(t, dt.Insert(data.NewDatum([]float32{float32(i % 10), 1}, 2, 0, 1, 0, group, []byte("{}"), 0), nil))
+	}
+	query := data.NewDatum([]float32{0, 1}, 2, 0, 1, 0, nil, nil, 0)
+	config := data.DefaultSearchConfig()
+	config.Limit = 4
+	seen := make(map[string]bool)
+	lastScore := 0.0
+	for page := 0; page < 9; page++ {
+		result, metadata, err := dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{4, 4, 4, 4, 4, 4, 4, 2, 0}[page], len(result))
+		if len(result) == 0 {
+			assert.Equal(t, "", metadata.NextPageToken)
+		}
+		for _, scoredDatum := range result {
+			group := string(scoredDatum.Datum.Key.GroupLabel)
+			assert.False(t, seen[group], group)
+			assert.LessOrEqual(t, lastScore, scoredDatum.Score)
+			seen[group] = true
+			lastScore = scoredDatum.Score
+		}
+		config.PageToken = metadata.NextPageToken
+	}
+	assert.Equal(t, 30, len(seen))
+
+	config.PageToken = "not a token"
+	_, _, err = dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+	assert.Equal(t, data.ErrInvalidPageToken, err)
+
+	// Diversified pages skip datums a cursor would not return again
+	config.PageToken = ""
+	config.MaxPerGroup = 1
+	result, metadata, err := dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(result))
+	assert.Equal(t, "", metadata.NextPageToken)
+	config.PageToken = data.NextCursor(result, nil).Token()
+	_, _, err = dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+	assert.NotNil(t, err)
+	config.MaxPerGroup = 0
+	config.MmrLambda = 0.5
+	_, _, err = dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+	assert.NotNil(t, err)
+}
+
+func TestDataSearchContextExclusionAndNegatives(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	dt, err := data.NewData(&pb.DataConfig{Name: "context", TargetN: 1000}, dir)
+	assert.Nil(t, err)
+	defer dt.Close()
+	datums := make([]*pb.Datum, 100)
+	for i := range datums {
+		datums[i] = data.NewDatum([]float32{float32(i), 1}, 2, 0, 1, 0, []byte(fmt.Sprintf(`{"i": %v}`, i)), []byte("{}"), 0)
+		assert.Nil(t, dt.Insert(datums[i], nil))
+	}
+	query := data.NewDatum([]float32{50.25, 1}, 2, 0, 1, 0, nil, nil, 0) // no ties in scores
+	config := data.DefaultSearchConfig()
+	config.Limit = 3
+	features := func(result []*pb.ScoredDatum) []float32 {
+		list := make([]float32, len(result))
+		for i, scoredDatum := range result {
+			list[i] = scoredDatum.Datum.Key.Feature[0]
+		}
+		return list
+	}
+
+	result, _, err := dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []float32{50, 51, 49}, features(result))
+
+	// Excluded datums are skipped, the page is still full
+	context := &pb.SearchContext{
+		ExcludedKey:        []*pb.DatumKey{datums[50].Key},
+		ExcludedGroupLabel: [][]byte{datums[51].Key.GroupLabel},
+	}
+	result, _, err = dt.MultiAggregatedSearch([]*pb.Datum{query}, config, context)
+	assert.Nil(t, err)
+	assert.Equal(t, []float32{49, 52, 48}, features(result))
+
+	// Datums on the side of the negative datum are pushed down
+	context = &pb.SearchContext{
+		NegativeDatum: []*pb.Datum{datums[51]},
+	}
+	result, _, err = dt.MultiAggregatedSearch([]*pb.Datum{query}, config, context)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(result))
+	for _, scoredDatum := range result {
+		assert.Less(t, scoredDatum.Datum.Key.Feature[0], float32(50.25))
+		assert.InDelta(t, -0.75, scoredDatum.Score, 1e-6)
+	}
+
+	config.PageToken = data.NextCursor(result, nil).Token()
+	_, _, err = dt.MultiAggregatedSearch([]*pb.Datum{query}, config, context)
+	assert.NotNil(t, err)
+
+	// A prioritized context replaces the score sources are paged by
+	context = &pb.SearchContext{
+		Datum:      []*pb.Datum{datums[10]},
+		Prioritize: true,
+	}
+	_, _, err = dt.MultiAggregatedSearch([]*pb.Datum{query}, config, context)
+	assert.NotNil(t, err)
+}
+
+type failingSource struct {
+	*data.Data
+}
+
+func (f *failingSource) StreamSearch(datum *pb.Datum, scoredDatumStream chan<- *pb.ScoredDatum, queryWaitGroup *sync.WaitGroup, config *pb.SearchConfig, context *pb.SearchContext) error {
+	queryWaitGroup.Done()
+	return errors.New("Source failure")
+}
+
+func (f *failingSource) GetID() string {
+	return "failing"
+}
+
+// recordingSource records the configs of the searches it gets
+type recordingSource struct {
+	*data.Data
+	sync.Mutex
+	configs []*pb.SearchConfig
+}
+
+func (r *recordingSource) StreamSearch(datum *pb.Datum, scoredDatumStream chan<- *pb.ScoredDatum, queryWaitGroup *sync.WaitGroup, config *pb.SearchConfig, context *pb.SearchContext) error {
+	r.Lock()
+	r.configs = append(r.configs, config)
+	r.Unlock()
+	return r.Data.StreamSearch(datum, scoredDatumStream, queryWaitGroup, config, context)
+}
+
+func (r *recordingSource) GetID() string {
+	return "recording"
+}
+
+func TestDataDeterministicSearch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	dt, err := data.NewData(&pb.DataConfig{Name: "deterministic", TargetN: 1000}, dir)
+	assert.Nil(t, err)
+	defer dt.Close()
+	peer, err := data.NewData(&pb.DataConfig{Name: "deterministic-peer", TargetN: 1000}, dir)
+	assert.Nil(t, err)
+	defer peer.Close()
+	keys := make([][]byte, 0)
+	for i := 0; i < 40; i++ {
+		// every datum has the same score
+		datum := data.NewDatum([]float32{1, 1}, 2, 0, 1, 0, []byte(fmt.Sprintf(`{"i": %v}`, i)), []byte("{}"), 0)
+		keyByte, _ := data.GetKeyAsBytes(datum)
+		keys = append(keys, keyByte)
+		if i%2 == 0 {
+			assert.Nil(t, dt.Insert(datum, nil))
+		} else {
+			assert.Nil(t, peer.Insert(datum, nil))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+	assert.Nil(t, dt.AddSource(peer))
+	defer dt.Sources.Flush() // Close would move the data to the sources
+	query := data.NewDatum([]float32{0, 1}, 2, 0, 1, 0, nil, nil, 0)
+	config := data.DefaultSearchConfig()
+	config.Limit = 5
+	config.Deterministic = true
+	for run := 0; run < 3; run++ {
+		result, metadata, err := dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+		assert.Nil(t, err)
+		assert.True(t, metadata.Consistent)
+		assert.Equal(t, uint32(1), metadata.SourcesAnswered)
+		assert.Equal(t, 5, len(result))
+		for i, scoredDatum := range result {
+			keyByte, _ := data.GetKeyAsBytes(scoredDatum.Datum)
+			assert.Equal(t, keys[i], keyByte)
+		}
+	}
+
+	// Sources are counted once for every query datum
+	query2 := data.NewDatum([]float32{1, 0}, 2, 0, 1, 0, nil, nil, 0)
+	_, metadata, err := dt.MultiAggregatedSearch([]*pb.Datum{query, query2}, config, nil)
+	assert.Nil(t, err)
+	assert.True(t, metadata.Consistent)
+	assert.Equal(t, uint32(1), metadata.SourcesQueried)
+	assert.Equal(t, uint32(1), metadata.SourcesAnswered)
+
+	// Every query datum is a separate query for peers, the config is not changed
+	recording := &recordingSource{Data: peer}
+	assert.Nil(t, dt.AddSource(recording))
+	config.Uuid = "query"
+	_, _, err = dt.MultiAggregatedSearch([]*pb.Datum{query, query2}, config, nil)
+	assert.Nil(t, err)
+	uuids := make([]string, 0, len(recording.configs))
+	for _, recordedConfig := range recording.configs {
+		uuids = append(uuids, recordedConfig.Uuid)
+	}
+	sort.Strings(uuids)
+	assert.Equal(t, []string{"query-0", "query-1"}, uuids)
+	assert.Equal(t, uint64(1000), config.Timeout)
+	config.Uuid = ""
+	dt.Sources.Delete("recording")
+
+	// A deterministic search fails unless every source answers
+	assert.Nil(t, dt.AddSource(&failingSource{peer}))
+	_, metadata, err = dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+	assert.NotNil(t, err)
+	assert.False(t, metadata.Consistent)
+	config.Deterministic = false
+	config.CacheDuration = 60
+	for run := 0; run < 2; run++ {
+		// Partial results are not cached
+		result, metadata, err := dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+		assert.Nil(t, err)
+		assert.False(t, metadata.Consistent)
+		assert.Equal(t, uint32(2), metadata.SourcesQueried)
+		assert.Equal(t, 5, len(result))
+	}
+	dt.Sources.Delete("failing")
+	for run := 0; run < 2; run++ {
+		// The second search is cached with the stats of the first one
+		result, metadata, err := dt.MultiAggregatedSearch([]*pb.Datum{query}, config, nil)
+		assert.Nil(t, err)
+		assert.True(t, metadata.Consistent)
+		assert.Equal(t, uint32(1), metadata.SourcesQueried)
+		assert.Equal(t, uint32(1), metadata.SourcesAnswered)
+		assert.Equal(t, 5, len(result))
+	}
+}
@@ -0,0 +1,108 @@
+package data
+
+import (
+	"encoding/json"
+
+	"github.com/bgokden/veri/util"
+	pb "github.com/bgokden/veri/veriservice"
+	"github.com/jinzhu/copier"
+)
+
+// negativeExpansionFactor is how many more datums a source returns so that negative datums can re-rank them
+const negativeExpansionFactor = 4
+
+// Exclusion is the set of keys and group labels of a search context that are not returned
+type Exclusion struct {
+	Keys        map[string]struct{}
+	GroupLabels map[string]struct{}
+}
+
+// GetExclusion returns the exclusion of a search context, nil if nothing is excluded
+func GetExclusion(context *pb.SearchContext) *Exclusion {
+	if len(context.GetExcludedKey()) == 0 && len(context.GetExcludedGroupLabel()) == 0 {
+		return nil
+	}
+	e := &Exclusion{
+		Keys:        make(map[string]struct{}, len(context.GetExcludedKey())),
+		GroupLabels: make(map[string]struct{}, len(context.GetExcludedGroupLabel())),
+	}
+	for _, key := range context.GetExcludedKey() {
+		if keyByte, err := GetKeyAsBytes(&pb.Datum{Key: key}); err == nil {
+			e.Keys[GetMapKey(keyByte)] = struct{}{}
+		}
+	}
+	for _, groupLabel := range context.GetExcludedGroupLabel() {
+		e.GroupLabels[util.EncodeToString(groupLabel)] = struct{}{}
+	}
+	return e
+}
+
+// Excludes is true if the key or the group label of datum is excluded
+// A nil exclusion excludes nothing
+func (e *Exclusion) Excludes(datum *pb.Datum) bool {
+	if e == nil {
+		return false
+	}
+	if len(e.GroupLabels) > 0 {
+		if _, ok := e.GroupLabels[util.EncodeToString(datum.GetKey().GetGroupLabel())]; ok {
+			return true
+		}
+	}
+	if len(e.Keys) > 0 {
+		keyByte, err := GetKeyAsBytes(datum)
+		if err != nil {
+			return false
+		}
+		_, ok := e.Keys[GetMapKey(keyByte)]
+		return ok
+	}
+	return false
+}
+
+// NegativeScore returns the weighted best score of feature against the negative datums of context
+// Subtracting it pushes datums similar to a negative datum down with similarities,
+// with distances datums far from every negative datum are pulled up
+func NegativeScore(context *pb.SearchContext, scoreFunc func(arr1 []float32, arr2 []float32) float64, higherIsBetter bool, feature []float32) float64 {
+	negatives := context.GetNegativeDatum()
+	if len(negatives) == 0 {
+		return 0
+	}
+	best := scoreFunc(feature, negatives[0].GetKey().GetFeature())
+	for _, negative := range negatives[1:] {
+		score := scoreFunc(feature, negative.GetKey().GetFeature())
+		if (higherIsBetter && score > best) || (!higherIsBetter && score < best) {
+			best = score
+		}
+	}
+	weight := context.GetNegativeWeight()
+	if weight == 0 {
+		weight = 1
+	}
+	return weight * best
+}
+
+// contextSearchConfig returns the config searching the local data for the results of config with context
+// Excluded and re-ranked datums are dropped at the merge, so more datums are collected
+func contextSearchConfig(config *pb.SearchConfig, context *pb.SearchContext) *pb.SearchConfig {
+	excluded := len(context.GetExcludedKey()) + len(context.GetExcludedGroupLabel())
+	hasNegatives := len(context.GetNegativeDatum()) > 0
+	if excluded == 0 && !hasNegatives {
+		return config
+	}
+	var contextConfig pb.SearchConfig
+	copier.Copy(&contextConfig, config)
+	contextConfig.Limit = config.Limit + uint32(excluded)
+	if hasNegatives {
+		contextConfig.Limit *= negativeExpansionFactor
+	}
+	return &contextConfig
+}
+
+// EncodeSearchContext encodes the context for query cache keys, a nil context is empty
+func EncodeSearchContext(context *pb.SearchContext) []byte {
+	if context == nil {
+		return nil
+	}
+	marshalled, _ := json.Marshal(context)
+	return marshalled
+}
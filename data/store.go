@@ -0,0 +1,525 @@
+package data
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"os"
+	"path"
+	"sync"
+	"time"
+)
+
+const (
+	storeOpInsert    = byte(1)
+	storeOpDelete    = byte(2)
+	storeOpTombstone = byte(3)
+	storeOpQuantizer = byte(4)
+	storeOpCoded     = byte(5)
+	storeOpDeleteKey = byte(6)
+
+	storeLogFileName      = "wal.log"
+	storeSnapshotFileName = "snapshot.save"
+	storeHeaderSize       = 8 // crc32 + body length
+)
+
+// StoreRecord is a single mutation of DBMap as it is written to disk
+// Coded records have the code and the map key of a codes only entry
+type StoreRecord struct {
+	Op        byte
+	ExprireAt int64
+	Key       []byte
+	Value     []byte
+	Code      []byte
+	MapKey    []byte
+	entry     *DBMapEntry // keeps the arena blocks of Key and Value alive until the record is written
+}
+
+// Store is an append-only write-ahead log with periodic snapshots
+// It keeps DBMap recoverable after a crash or restart
+type Store struct {
+	sync.Mutex
+	Path         string
+	LogPath      string
+	SnapshotPath string
+	file         *os.File
+	Records      uint64
+	Dirty        bool
+}
+
+// NewStore opens or creates a store under the given folder
+func NewStore(storePath string) (*Store, error) {
+	err := os.MkdirAll(storePath, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{
+		Path:         storePath,
+		LogPath:      path.Join(storePath, storeLogFileName),
+		SnapshotPath: path.Join(storePath, storeSnapshotFileName),
+	}
+	return s, nil
+}
+
+func encodeStoreRecord(record *StoreRecord) []byte {
+	bodySize := 1 + 8 + binary.MaxVarintLen64 + len(record.Key) + binary.MaxVarintLen64 + len(record.Value)
+	if record.Op == storeOpCoded {
+		bodySize += binary.MaxVarintLen64 + len(record.Code) + binary.MaxVarintLen64 + len(record.MapKey)
+	}
+	buf := make([]byte, storeHeaderSize+bodySize)
+	i := storeHeaderSize
+	buf[i] = record.Op
+	i++
+	binary.LittleEndian.PutUint64(buf[i:], uint64(record.ExprireAt))
+	i += 8
+	i += binary.PutUvarint(buf[i:], uint64(len(record.Key)))
+	i += copy(buf[i:], record.Key)
+	i += binary.PutUvarint(buf[i:], uint64(len(record.Value)))
+	i += copy(buf[i:], record.Value)
+	if record.Op == storeOpCoded {
+		i += binary.PutUvarint(buf[i:], uint64(len(record.Code)))
+		i += copy(buf[i:], record.Code)
+		i += binary.PutUvarint(buf[i:], uint64(len(record.MapKey)))
+		i += copy(buf[i:], record.MapKey)
+	}
+	body := buf[storeHeaderSize:i]
+	binary.LittleEndian.PutUint32(buf[0:], crc32.ChecksumIEEE(body))
+	binary.LittleEndian.PutUint32(buf[4:], uint32(len(body)))
+	return buf[:i]
+}
+
+func decodeStoreRecord(body []byte) (*StoreRecord, error) {
+	if len(body) < 9 {
+		return nil, errors.New("Store record is too short")
+	}
+	record := &StoreRecord{
+		Op:        body[0],
+		ExprireAt: int64(binary.LittleEndian.Uint64(body[1:])),
+	}
+	i := 9
+	keySize, n := binary.Uvarint(body[i:])
+	if n <= 0 || uint64(len(body)-i-n) < keySize {
+		return nil, errors.New("Store record key is currupt")
+	}
+	i += n
+	record.Key = body[i : i+int(keySize)]
+	i += int(keySize)
+	valueSize, n := binary.Uvarint(body[i:])
+	if n <= 0 || uint64(len(body)-i-n) < valueSize {
+		return nil, errors.New("Store record value is currupt")
+	}
+	i += n
+	record.Value = body[i : i+int(valueSize)]
+	i += int(valueSize)
+	if record.Op != storeOpCoded {
+		return record, nil
+	}
+	codeSize, n := binary.Uvarint(body[i:])
+	if n <= 0 || uint64(len(body)-i-n) < codeSize {
+		return nil, errors.New("Store record code is currupt")
+	}
+	i += n
+	record.Code = body[i : i+int(codeSize)]
+	i += int(codeSize)
+	mapKeySize, n := binary.Uvarint(body[i:])
+	if n <= 0 || uint64(len(body)-i-n) < mapKeySize {
+		return nil, errors.New("Store record map key is currupt")
+	}
+	i += n
+	record.MapKey = body[i : i+int(mapKeySize)]
+	return record, nil
+}
+
+// readStoreRecords reads records until the end of file
+// It returns the offset after the last valid record and whether the file ends with a torn record
+// A record that can not be read before the last one is corruption, not a torn write, and it is returned as an error
+func readStoreRecords(reader io.Reader, recordFunction func(record *StoreRecord) error) (int64, bool, error) {
+	bufferedReader := bufio.NewReader(reader)
+	header := make([]byte, storeHeaderSize)
+	offset := int64(0)
+	for {
+		_, err := io.ReadFull(bufferedReader, header)
+		if err == io.EOF {
+			return offset, false, nil
+		}
+		if err == io.ErrUnexpectedEOF {
+			return offset, true, nil // torn header
+		}
+		if err != nil {
+			return offset, false, err
+		}
+		checksum := binary.LittleEndian.Uint32(header[0:])
+		bodySize := binary.LittleEndian.Uint32(header[4:])
+		body := make([]byte, bodySize)
+		_, err = io.ReadFull(bufferedReader, body)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return offset, true, nil // torn body
+		}
+		if err != nil {
+			return offset, false, err
+		}
+		record, err := decodeStoreRecord(body)
+		if crc32.ChecksumIEEE(body) != checksum {
+			err = errors.New("Store record checksum mismatch")
+		}
+		if err != nil {
+			if isZeroTail(bufferedReader) {
+				return offset, true, nil // the last record is torn
+			}
+			return offset, false, fmt.Errorf("Store record at offset %v is corrupt: %v", offset, err)
+		}
+		err = recordFunction(record)
+		if err != nil {
+			return offset, false, err
+		}
+		offset += int64(storeHeaderSize) + int64(bodySize)
+	}
+}
+
+// isZeroTail reads the rest of reader and is true if it is empty or only zeros
+// File systems may leave zeros after the last write of a crash
+func isZeroTail(reader io.Reader) bool {
+	buf := make([]byte, 4096)
+	for {
+		n, err := reader.Read(buf)
+		for _, b := range buf[:n] {
+			if b != 0 {
+				return false
+			}
+		}
+		if err == io.EOF {
+			return true
+		}
+		if err != nil {
+			return false
+		}
+	}
+}
+
+// Replay reads snapshot and then the log, and opens the log for appending
+func (s *Store) Replay(recordFunction func(record *StoreRecord) error) error {
+	s.Lock()
+	defer s.Unlock()
+	snapshotFile, err := os.Open(s.SnapshotPath)
+	if err == nil {
+		_, torn, err := readStoreRecords(snapshotFile, recordFunction)
+		snapshotFile.Close()
+		if err != nil {
+			return err
+		}
+		if torn {
+			// Snapshots are renamed into place after they are written
+			return errors.New("Store snapshot is truncated")
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	file, err := os.OpenFile(s.LogPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	validOffset, torn, err := readStoreRecords(file, func(record *StoreRecord) error {
+		s.Records++
+		return recordFunction(record)
+	})
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if torn {
+		// Drop a torn tail so that new records are appended after valid data
+		log.Printf("Store log %v has a torn record at offset %v, truncating\n", s.LogPath, validOffset)
+		err = file.Truncate(validOffset)
+		if err != nil {
+			file.Close()
+			return err
+		}
+	}
+	_, err = file.Seek(validOffset, io.SeekStart)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	s.file = file
+	return nil
+}
+
+// Append writes a record to the log, caller should hold the lock
+func (s *Store) Append(record *StoreRecord) error {
+	if s.file == nil {
+		return errors.New("Store is not open")
+	}
+	_, err := s.file.Write(encodeStoreRecord(record))
+	if err != nil {
+		return err
+	}
+	s.Records++
+	s.Dirty = true
+	return nil
+}
+
+// AppendBatch writes records to the log with a single write, caller should hold the lock
+func (s *Store) AppendBatch(records []*StoreRecord) error {
+	if s.file == nil {
+		return errors.New("Store is not open")
+	}
+	buf := make([]byte, 0, len(records)*64)
+	for _, record := range records {
+		buf = append(buf, encodeStoreRecord(record)...)
+	}
+	_, err := s.file.Write(buf)
+	if err != nil {
+		return err
+	}
+	s.Records += uint64(len(records))
+	s.Dirty = true
+	return nil
+}
+
+// Sync flushes the log to the disk
+func (s *Store) Sync() error {
+	s.Lock()
+	defer s.Unlock()
+	if s.file == nil || !s.Dirty {
+		return nil
+	}
+	s.Dirty = false
+	return s.file.Sync()
+}
+
+// Compact writes a snapshot of all records given by loop and truncates the log
+func (s *Store) Compact(loop func(recordFunction func(record *StoreRecord) error) error) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.file == nil {
+		return errors.New("Store is not open")
+	}
+	if s.Records == 0 {
+		return nil
+	}
+	start := time.Now()
+	tempPath := s.SnapshotPath + ".tmp"
+	snapshotFile, err := os.OpenFile(tempPath, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(snapshotFile)
+	count := 0
+	err = loop(func(record *StoreRecord) error {
+		count++
+		_, errWrite := writer.Write(encodeStoreRecord(record))
+		return errWrite
+	})
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = snapshotFile.Sync()
+	}
+	snapshotFile.Close()
+	if err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	err = os.Rename(tempPath, s.SnapshotPath)
+	if err != nil {
+		return err
+	}
+	// A crash before truncation only replays records again, which is idempotent
+	err = s.file.Truncate(0)
+	if err != nil {
+		return err
+	}
+	_, err = s.file.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	s.Records = 0
+	s.Dirty = false
+	log.Printf("Store compaction of %v entries took %s", count, time.Since(start))
+	return s.file.Sync()
+}
+
+// Close syncs and closes the log
+func (s *Store) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Sync()
+	if errClose := s.file.Close(); err == nil {
+		err = errClose
+	}
+	s.file = nil
+	return err
+}
+
+// OpenStore opens the store under DBPath and replays it into DBMap
+func (dt *Data) OpenStore() error {
+	if len(dt.DBPath) == 0 {
+		return nil
+	}
+	store, err := NewStore(dt.DBPath)
+	if err != nil {
+		return err
+	}
+	now := time.Now().Unix()
+	inserted := 0
+	deleted := 0
+	err = store.Replay(func(record *StoreRecord) error {
+		switch record.Op {
+		case storeOpInsert:
+			if record.ExprireAt != 0 && record.ExprireAt <= now {
+				// Expired while the node was down, a later delete is harmless
+				return nil
+			}
+			datum, err := ToDatum(record.Key, record.Value)
+			if err != nil {
+				return err
+			}
+			inserted++
+			_, _, err = dt.insertBDMapEntry(datum, record.ExprireAt)
+			if err == ErrStaleVersion || err == ErrDeleted {
+				return nil
+			}
+			return err
+		case storeOpCoded:
+			if record.ExprireAt != 0 && record.ExprireAt <= now {
+				return nil
+			}
+			inserted++
+			_, _, err := dt.insertCodedRecord(record)
+			if err == ErrStaleVersion || err == ErrDeleted {
+				return nil
+			}
+			return err
+		case storeOpQuantizer:
+			quantizer, err := quantizerOfRecord(record)
+			if err != nil {
+				return err
+			}
+			dt.Quantized.Lock()
+			dt.Quantized.Quantizer = quantizer
+			dt.Quantized.Unlock()
+		case storeOpDelete:
+			deleted++
+			dt.deleteBDMapEntry(GetMapKey(record.Key))
+		case storeOpDeleteKey:
+			deleted++
+			dt.deleteBDMapEntry(string(record.Key))
+		case storeOpTombstone:
+			if record.ExprireAt <= now {
+				return nil
+			}
+			deleted++
+			key := string(record.Key)
+			dt.Tombstones.Add(key, tombstoneOfRecord(record))
+			dt.deleteBDMapEntry(key)
+		}
+		return nil
+	})
+	if err != nil {
+		store.Close()
+		return err
+	}
+	if inserted > 0 || deleted > 0 {
+		log.Printf("Data %v replayed %v insertions and %v deletions\n", dt.Config.Name, inserted, deleted)
+	}
+	if dt.getQuantizer() != nil {
+		// Entries logged before the last quantizer are encoded with it
+		err = dt.loopDBMapWithKey(dt.requantizeEntry)
+		if err != nil {
+			store.Close()
+			return err
+		}
+	}
+	dt.Store = store
+	return nil
+}
+
+// insertCodedRecord inserts the codes only entry of a coded record with the current quantizer
+func (dt *Data) insertCodedRecord(record *StoreRecord) (string, *DBMapEntry, error) {
+	quantizer := dt.getQuantizer()
+	if quantizer == nil {
+		return "", nil, errors.New("Store record is coded without a quantizer")
+	}
+	datum, err := ToDatum(record.Key, record.Value)
+	if err != nil {
+		return "", nil, err
+	}
+	code := make([]byte, len(record.Code))
+	copy(code, record.Code)
+	key := string(record.MapKey)
+	entry, err := newCodedBDMapEntry(key, datum, record.ExprireAt, code, quantizer)
+	if err != nil {
+		return "", nil, err
+	}
+	datum.Key.Feature = quantizer.Decode(code)
+	return dt.storeBDMapEntry(key, datum, entry)
+}
+
+// entryRecord returns the insert record of entry, codes only entries have coded records
+// The record refers to the blocks of entry without copying them
+func entryRecord(entry *DBMapEntry) *StoreRecord {
+	if entry.CodesOnly() {
+		return &StoreRecord{
+			Op:        storeOpCoded,
+			ExprireAt: entry.ExprireAt,
+			Key:       entry.Key,
+			Value:     entry.Value,
+			Code:      entry.Code,
+			MapKey:    []byte(entry.mapKey),
+			entry:     entry,
+		}
+	}
+	return &StoreRecord{
+		Op:        storeOpInsert,
+		ExprireAt: entry.ExprireAt,
+		Key:       entry.Key,
+		Value:     entry.Value,
+		entry:     entry,
+	}
+}
+
+// CompactStore rewrites the snapshot from DBMap and tombstones and truncates the log
+// The quantizer is written before entries, codes only entries of an older quantizer are encoded again
+func (dt *Data) CompactStore() error {
+	if dt.Store == nil {
+		return nil
+	}
+	return dt.Store.Compact(func(recordFunction func(record *StoreRecord) error) error {
+		err := dt.Tombstones.LoopTombstones(func(key string, tombstone Tombstone) error {
+			return recordFunction(tombstoneRecord(key, tombstone))
+		})
+		if err != nil {
+			return err
+		}
+		quantizer := dt.getQuantizer()
+		if quantizer != nil {
+			record, err := quantizerRecord(quantizer)
+			if err != nil {
+				return err
+			}
+			err = recordFunction(record)
+			if err != nil {
+				return err
+			}
+		}
+		return dt.LoopDBMap(func(entry *DBMapEntry) error {
+			record := entryRecord(entry)
+			if entry.CodesOnly() && entry.Quantizer != quantizer {
+				datumKey, err := entry.DatumKey()
+				if err != nil {
+					return err
+				}
+				record.Code = quantizer.Encode(datumKey.Feature)
+			}
+			return recordFunction(record)
+		})
+	})
+}
@@ -0,0 +1,120 @@
+package data_test
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	data "github.com/bgokden/veri/data"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+func countEntries(dt *data.Data) int {
+	count := 0
+	dt.LoopDBMap(func(entry *data.DBMapEntry) error {
+		count++
+		return nil
+	})
+	return count
+}
+
+func TestDataPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	config := &pb.DataConfig{
+		Name:    "persisted",
+		Version: 0,
+		TargetN: 1000,
+	}
+
+	dt, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	err = dt.Insert(data.NewDatum([]float32{0.1, 0.2, 0.3}, 3, 0, 1, 0, []byte("a"), []byte("a"), 0), nil)
+	assert.Nil(t, err)
+	err = dt.Insert(data.NewDatum([]float32{0.2, 0.3, 0.4}, 3, 0, 1, 0, []byte("b"), []byte("b"), 0), nil)
+	assert.Nil(t, err)
+	err = dt.Insert(data.NewDatum([]float32{0.2, 0.3, 0.7}, 3, 0, 1, 0, []byte("c"), []byte("c"), 0), &pb.InsertConfig{TTL: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, countEntries(dt))
+	dt.Close()
+
+	time.Sleep(2 * time.Second)
+
+	// Replay from the log, expired entry should be skipped
+	dt2, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, countEntries(dt2))
+
+	err = dt2.CompactStore()
+	assert.Nil(t, err)
+	logInfo, err := os.Stat(path.Join(dir, config.Name, "wal.log"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), logInfo.Size())
+	err = dt2.Insert(data.NewDatum([]float32{0.5, 0.3, 0.7}, 3, 0, 1, 0, []byte("d"), []byte("d"), 0), nil)
+	assert.Nil(t, err)
+	dt2.Close()
+
+	// Replay from the snapshot and the log
+	dt3, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	defer dt3.Close()
+	assert.Equal(t, 3, countEntries(dt3))
+}
+
+func TestDataStoreCorruption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "veri-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	config := &pb.DataConfig{
+		Name:    "corrupted",
+		Version: 0,
+		TargetN: 1000,
+	}
+	dt, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	for _, label := range []string{"a", "b", "c"} {
+		assert.Nil(t, dt.Insert(data.NewDatum([]float32{0.1, 0.2, 0.3}, 3, 0, 1, 0, []byte(label), []byte(label), 0), nil))
+	}
+	dt.Close()
+	logPath := path.Join(dir, config.Name, "wal.log")
+	logInfo, err := os.Stat(logPath)
+	assert.Nil(t, err)
+
+	// A torn record at the end is truncated
+	file, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0644)
+	assert.Nil(t, err)
+	_, err = file.Write([]byte{1, 2, 3, 4, 5, 0, 0, 0, 1})
+	assert.Nil(t, err)
+	file.Close()
+	dt2, err := data.NewData(config, dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, countEntries(dt2))
+	dt2.Close()
+	tornInfo, err := os.Stat(logPath)
+	assert.Nil(t, err)
+	assert.Equal(t, logInfo.Size(), tornInfo.Size())
+
+	// A corrupt record before valid records fails the replay
+	file, err = os.OpenFile(logPath, os.O_RDWR, 0644)
+	assert.Nil(t, err)
+	_, err = file.WriteAt([]byte{0xff}, 12)
+	assert.Nil(t, err)
+	file.Close()
+	_, err = data.NewData(config, dir)
+	assert.NotNil(t, err)
+	corruptInfo, err := os.Stat(logPath)
+	assert.Nil(t, err)
+	assert.Equal(t, logInfo.Size(), corruptInfo.Size())
+}
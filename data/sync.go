@@ -1,6 +1,11 @@
 package data
 
 import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+
 	pb "github.com/bgokden/veri/veriservice"
 )
 
@@ -87,71 +92,120 @@ type StreamCollector struct {
 	DatumStream chan<- *pb.Datum
 }
 
-// func (dt *Data) StreamAll(datumStream chan<- *pb.Datum) error {
-// 	return dt.StreamSample(datumStream, 1)
-// }
-
-// func (dt *Data) StreamSample(datumStream chan<- *pb.Datum, fraction float64) error {
-// 	c := &StreamCollector{
-// 		DatumStream: datumStream,
-// 	}
-// 	stream := dt.DB.NewStream()
-// 	// db.NewStreamAt(readTs) for managed mode.
-
-// 	// -- Optional settings
-// 	stream.NumGo = 16                     // Set number of goroutines to use for iteration.
-// 	stream.Prefix = nil                   // Leave nil for iteration over the whole DB.
-// 	stream.LogPrefix = "Badger.Streaming" // For identifying stream logs. Outputs to Logger.
-
-// 	// ChooseKey is called concurrently for every key. If left nil, assumes true by default.
-// 	if fraction < 1 {
-// 		stream.ChooseKey = func(item *badger.Item) bool {
-// 			return rand.Float64() < fraction
-// 		}
-// 	} else {
-// 		stream.ChooseKey = nil
-// 	}
-
-// 	// KeyToList is called concurrently for chosen keys. This can be used to convert
-// 	// Badger data into custom key-values. If nil, uses stream.ToList, a default
-// 	// implementation, which picks all valid key-values.
-// 	stream.KeyToList = nil
-
-// 	// -- End of optional settings.
-
-// 	// Send is called serially, while Stream.Orchestrate is running.
-
-// 	stream.Send = c.Send
-
-// 	// Run the stream
-// 	if err := stream.Orchestrate(context.Background()); err != nil {
-// 		return nil
-// 	}
-// 	// Done.
-// 	return nil
-// }
+// StreamAll streams every datum of local data
+func (dt *Data) StreamAll(datumStream chan<- *pb.Datum) error {
+	return dt.StreamSample(datumStream, 1)
+}
 
-// // Send collects the results
-// func (c *StreamCollector) Send(buf *z.Buffer) error {
-// 	err := buf.SliceIterate(func(s []byte) error {
-// 		kv := new(pbp.KV)
-// 		if err := kv.Unmarshal(s); err != nil {
-// 			return err
-// 		}
+// StreamSample streams a random fraction of local data
+func (dt *Data) StreamSample(datumStream chan<- *pb.Datum, fraction float64) error {
+	return dt.StreamData(context.Background(), datumStream, &pb.StreamConfig{
+		Fraction: fraction,
+	})
+}
 
-// 		if kv.StreamDone == true {
-// 			return nil
-// 		}
+// StreamData streams local data passing the sampling fraction and filters of config
+// Fraction 0 or larger than 1 streams everything
+// The scan stops with the error of ctx when ctx is done
+func (dt *Data) StreamData(ctx context.Context, datumStream chan<- *pb.Datum, config *pb.StreamConfig) error {
+	if config == nil {
+		config = &pb.StreamConfig{}
+	}
+	fraction := config.GetFraction()
+	c := &Collector{
+		Filters:      config.GetFilters(),
+		GroupFilters: config.GetGroupFilters(),
+	}
+	return dt.LoopDBMap(func(entry *DBMapEntry) error {
+		if fraction > 0 && fraction < 1 && rand.Float64() >= fraction {
+			return nil
+		}
+		datum, err := entry.Datum()
+		if err != nil {
+			return err
+		}
+		if !c.PassesFilters(datum) {
+			return nil
+		}
+		select {
+		case datumStream <- datum:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
 
-// 		datum, errInner := ToDatum(kv.Key, kv.Value)
-// 		if errInner != nil {
-// 			return errInner
-// 		}
-// 		c.DatumStream <- datum
-// 		return nil
-// 	})
-// 	return err
-// }
+// AggregatedStreamData streams local data and data of all sources
+// Duplicates of replicated datums are sent only once, the hashed key of every streamed datum is kept
+// so memory grows by about 32 bytes per datum of the export
+// The first error of the local scan or a source stops every other stream and is returned
+func (dt *Data) AggregatedStreamData(ctx context.Context, datumStream chan<- *pb.Datum, config *pb.StreamConfig) error {
+	if dt.Sources == nil {
+		err := dt.InitData()
+		if err != nil {
+			return err
+		}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	mergeStream := make(chan *pb.Datum, 100)
+	var streamWaitGroup sync.WaitGroup
+	var errOnce sync.Once
+	var streamErr error
+	fail := func(err error) {
+		errOnce.Do(func() {
+			streamErr = err
+			cancel()
+		})
+	}
+	streamWaitGroup.Add(1)
+	go func() {
+		defer streamWaitGroup.Done()
+		if err := dt.StreamData(ctx, mergeStream, config); err != nil {
+			fail(err)
+		}
+	}()
+	for _, sourceItem := range dt.Sources.Items() {
+		source := sourceItem.Object.(DataSource)
+		streamWaitGroup.Add(1)
+		go func() {
+			defer streamWaitGroup.Done()
+			if err := source.StreamData(ctx, mergeStream, config); err != nil {
+				fail(fmt.Errorf("StreamData error from %v: %v", source.GetID(), err))
+			}
+		}()
+	}
+	go func() {
+		streamWaitGroup.Wait()
+		close(mergeStream)
+	}()
+	seen := make(map[string]struct{})
+	for datum := range mergeStream {
+		if ctx.Err() != nil {
+			continue // drain until every stream stops
+		}
+		keyByte, err := GetKeyAsBytes(datum)
+		if err != nil {
+			continue
+		}
+		mapKey := GetMapKey(keyByte)
+		if _, ok := seen[mapKey]; ok {
+			continue
+		}
+		seen[mapKey] = struct{}{}
+		select {
+		case datumStream <- datum:
+		case <-ctx.Done():
+			fail(ctx.Err())
+		}
+	}
+	if streamErr == nil && ctx.Err() != nil {
+		// The parent context is done, cancel of this call is deferred
+		return ctx.Err()
+	}
+	return streamErr
+}
 
 // InsertStreamCollector collects results
 type InsertStreamCollector struct {
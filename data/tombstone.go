@@ -0,0 +1,289 @@
+package data
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"log"
+	"sync"
+	"time"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+// DefaultTombstoneTTL is how long a deleted key is remembered in seconds
+const DefaultTombstoneTTL = 24 * 60 * 60
+
+// deleteFilterHops is how many hops a delete by filters is forwarded between sources
+const deleteFilterHops = 3
+
+// ErrDeleted is returned when a datum is not newer than the tombstone of its key
+var ErrDeleted = errors.New("Datum is deleted")
+
+// Tombstone marks a deleted key so that copies from peers are not inserted again
+// Unversioned deletes keep the hash of the deleted value, only copies of that value are covered
+type Tombstone struct {
+	ExprireAt int64
+	Version   uint64
+	ValueHash uint64
+}
+
+// GetValueHash returns the hash of an unversioned datum value in tombstones, it is never 0
+func GetValueHash(value *pb.DatumValue) uint64 {
+	hash := fnv.New64a()
+	hash.Write(value.GetLabel())
+	if sum := hash.Sum64(); sum != 0 {
+		return sum
+	}
+	return 1
+}
+
+// Tombstones keeps deleted keys until their TTL expires
+type Tombstones struct {
+	sync.RWMutex
+	Entries map[string]Tombstone
+}
+
+// Add marks key as deleted, an existing tombstone keeps the higher version with its value hash and the later expiry
+func (ts *Tombstones) Add(key string, tombstone Tombstone) {
+	ts.Lock()
+	defer ts.Unlock()
+	if ts.Entries == nil {
+		ts.Entries = make(map[string]Tombstone)
+	}
+	if old, ok := ts.Entries[key]; ok {
+		if old.Version > tombstone.Version {
+			tombstone.Version = old.Version
+			tombstone.ValueHash = old.ValueHash
+		}
+		if old.ExprireAt > tombstone.ExprireAt {
+			tombstone.ExprireAt = old.ExprireAt
+		}
+	}
+	ts.Entries[key] = tombstone
+}
+
+// Covers is true if a datum with value under key is deleted
+// A versioned tombstone covers equal and lower versions, an unversioned one only the unversioned value deleted with it
+// so that a new unversioned datum can be inserted after the delete
+func (ts *Tombstones) Covers(key string, value *pb.DatumValue, now int64) bool {
+	ts.RLock()
+	defer ts.RUnlock()
+	tombstone, ok := ts.Entries[key]
+	if !ok || tombstone.ExprireAt <= now {
+		return false
+	}
+	if tombstone.Version > 0 {
+		return tombstone.Version >= value.GetVersion()
+	}
+	return value.GetVersion() == 0 && tombstone.ValueHash != 0 && tombstone.ValueHash == GetValueHash(value)
+}
+
+// Seen is true if a delete of key at version is already applied
+func (ts *Tombstones) Seen(key string, version uint64, now int64) bool {
+	ts.RLock()
+	defer ts.RUnlock()
+	tombstone, ok := ts.Entries[key]
+	return ok && tombstone.ExprireAt > now && tombstone.Version >= version
+}
+
+// Expire drops tombstones that are older than their TTL
+func (ts *Tombstones) Expire(now int64) {
+	ts.Lock()
+	defer ts.Unlock()
+	for key, tombstone := range ts.Entries {
+		if tombstone.ExprireAt <= now {
+			delete(ts.Entries, key)
+		}
+	}
+}
+
+// Len is the number of tombstones including expired ones not dropped yet
+func (ts *Tombstones) Len() int {
+	ts.RLock()
+	defer ts.RUnlock()
+	return len(ts.Entries)
+}
+
+// LoopTombstones runs tombstoneFunction on every tombstone that is not expired
+func (ts *Tombstones) LoopTombstones(tombstoneFunction func(key string, tombstone Tombstone) error) error {
+	ts.RLock()
+	defer ts.RUnlock()
+	now := time.Now().Unix()
+	for key, tombstone := range ts.Entries {
+		if tombstone.ExprireAt <= now {
+			continue
+		}
+		err := tombstoneFunction(key, tombstone)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTombstoneTTL returns the tombstone TTL of a delete in seconds
+func GetTombstoneTTL(config *pb.DeleteConfig) int64 {
+	if config.GetTombstoneTTL() == 0 {
+		return DefaultTombstoneTTL
+	}
+	return int64(config.GetTombstoneTTL())
+}
+
+func tombstoneRecord(key string, tombstone Tombstone) *StoreRecord {
+	value := make([]byte, 16)
+	binary.LittleEndian.PutUint64(value, tombstone.Version)
+	binary.LittleEndian.PutUint64(value[8:], tombstone.ValueHash)
+	return &StoreRecord{
+		Op:        storeOpTombstone,
+		ExprireAt: tombstone.ExprireAt,
+		Key:       []byte(key),
+		Value:     value,
+	}
+}
+
+func tombstoneOfRecord(record *StoreRecord) Tombstone {
+	tombstone := Tombstone{
+		ExprireAt: record.ExprireAt,
+	}
+	if len(record.Value) >= 8 {
+		tombstone.Version = binary.LittleEndian.Uint64(record.Value)
+	}
+	if len(record.Value) >= 16 {
+		tombstone.ValueHash = binary.LittleEndian.Uint64(record.Value[8:])
+	}
+	return tombstone
+}
+
+// deleteWithTombstone deletes the entry of a map key and keeps a tombstone until exprireAt
+// The tombstone has the higher version of value and the entry, an unversioned one has the hash of the deleted value
+// It returns the value of the tombstone and whether an entry was deleted
+func (dt *Data) deleteWithTombstone(key string, value *pb.DatumValue, exprireAt int64) (*pb.DatumValue, bool, error) {
+	if dt.Store != nil {
+		dt.Store.Lock()
+		defer dt.Store.Unlock()
+	}
+	lock := dt.keyLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+	found := false
+	if stored, ok := dt.DBMap.Load(key); ok {
+		if entry, ok := stored.(*DBMapEntry); ok {
+			found = true
+			if entryValue, err := entry.DatumValue(); err == nil && entryValue.GetVersion() >= value.GetVersion() {
+				value = entryValue
+			}
+		}
+	}
+	tombstone := Tombstone{
+		ExprireAt: exprireAt,
+		Version:   value.GetVersion(),
+	}
+	if tombstone.Version == 0 && (found || len(value.GetLabel()) > 0) {
+		// Without a value nothing is deleted here, copies of peers are not covered
+		tombstone.ValueHash = GetValueHash(value)
+	}
+	dt.Tombstones.Add(key, tombstone)
+	dt.deleteBDMapEntry(key)
+	if dt.Store == nil {
+		return value, found, nil
+	}
+	return value, found, dt.Store.Append(tombstoneRecord(key, tombstone))
+}
+
+// Remove deletes datums by key, or every datum passing the filters when no datum is given
+// Deleted keys keep a tombstone so that older copies from peers are not inserted again
+// Every node forwards the delete to its sources, keys are forwarded only if their tombstone is new here
+// so a delete by keys floods the cluster once, a delete by filters is forwarded for deleteFilterHops hops
+// with its Uuid, nodes drop a delete by filters they already applied
+func (dt *Data) Remove(request *pb.DeleteRequest) (uint64, error) {
+	if dt.Initialized == false {
+		err := dt.InitData()
+		if err != nil {
+			return 0, err
+		}
+	}
+	config := request.GetConfig()
+	now := time.Now().Unix()
+	exprireAt := now + GetTombstoneTTL(config)
+	datumList := request.GetDatum()
+	keys := make([]string, 0, len(datumList)) // map keys of datums found by the filters
+	forwarded := &pb.DeleteRequest{
+		DataName:     request.GetDataName(),
+		Filters:      request.GetFilters(),
+		GroupFilters: request.GetGroupFilters(),
+		Config: &pb.DeleteConfig{
+			TombstoneTTL: config.GetTombstoneTTL(),
+			Count:        config.GetCount() + 1,
+			Uuid:         config.GetUuid(),
+		},
+	}
+	if len(datumList) == 0 {
+		if len(request.GetFilters()) == 0 && len(request.GetGroupFilters()) == 0 {
+			return 0, errors.New("Delete needs a datum or a filter")
+		}
+		c := &Collector{
+			Filters:      request.GetFilters(),
+			GroupFilters: request.GetGroupFilters(),
+		}
+		err := dt.loopDBMapWithKey(func(key string, entry *DBMapEntry) error {
+			datum, err := entry.Datum()
+			if err != nil {
+				return err
+			}
+			if c.PassesFilters(datum) {
+				datumList = append(datumList, datum)
+				keys = append(keys, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		forwarded.Filters = nil
+		forwarded.GroupFilters = nil
+	}
+	deleted := uint64(0)
+	for i, datum := range datumList {
+		if i >= len(keys) {
+			keyByte, err := GetKeyAsBytes(datum)
+			if err != nil {
+				return deleted, err
+			}
+			keys = append(keys, GetMapKey(keyByte))
+		}
+		seen := dt.Tombstones.Seen(keys[i], datum.GetValue().GetVersion(), now)
+		value, found, err := dt.deleteWithTombstone(keys[i], datum.GetValue(), exprireAt)
+		if err != nil {
+			return deleted, err
+		}
+		if found {
+			deleted++
+		}
+		if len(forwarded.Filters) == 0 && len(forwarded.GroupFilters) == 0 && (config.GetCount() == 0 || found || !seen) {
+			// Peers may not have the datum, the tombstone value is sent with the key
+			forwarded.Datum = append(forwarded.Datum, &pb.Datum{
+				Key:   datum.Key,
+				Value: value,
+			})
+		}
+	}
+	if deleted > 0 {
+		dt.setDirty(true)
+	}
+	forward := len(forwarded.Datum) > 0
+	if len(forwarded.Filters) > 0 || len(forwarded.GroupFilters) > 0 {
+		forward = config.GetCount() < deleteFilterHops
+	}
+	if forward && dt.Sources != nil {
+		for _, sourceItem := range dt.Sources.Items() {
+			source := sourceItem.Object.(DataSource)
+			_, err := source.Remove(forwarded)
+			if err != nil && CheckIfUnkownError(err) {
+				log.Printf("Sending Delete error %v\n", err.Error())
+			}
+		}
+	}
+	return deleted, nil
+}
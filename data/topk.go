@@ -0,0 +1,205 @@
+package data
+
+import (
+	"bytes"
+	"sort"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+// TopK keeps the best N scored datums
+// List is a heap with the worst datum at the root while datums are pushed, Sort orders it from the best
+// With TieBreak datums with the same score are ordered by their encoded keys, the lower key is better
+// A keyed top list keeps one datum per key, the index of every key in List is tracked
+type TopK struct {
+	List           []*pb.ScoredDatum
+	N              uint32
+	HigherIsBetter bool
+	TieBreak       bool
+	sorted         bool
+	keys           []string
+	index          map[string]int
+}
+
+// NewTopK returns an empty top list of size n
+func NewTopK(n uint32, higherIsBetter bool) TopK {
+	return TopK{
+		List:           make([]*pb.ScoredDatum, 0, min(int(n), 1024)),
+		N:              n,
+		HigherIsBetter: higherIsBetter,
+	}
+}
+
+// NewKeyedTopK returns an empty top list of size n which keeps the best datum of every key
+func NewKeyedTopK(n uint32, higherIsBetter bool) TopK {
+	t := NewTopK(n, higherIsBetter)
+	t.keys = make([]string, 0, cap(t.List))
+	t.index = make(map[string]int, cap(t.List))
+	return t
+}
+
+// Push adds scoredDatum if the list is not full or it is better than the worst datum
+// It returns true if scoredDatum is added
+func (t *TopK) Push(scoredDatum *pb.ScoredDatum) bool {
+	return t.PushKey("", scoredDatum)
+}
+
+// PushKey adds scoredDatum with key like Push
+// In a keyed top list a datum with a key in the list replaces it in place if it is better
+func (t *TopK) PushKey(key string, scoredDatum *pb.ScoredDatum) bool {
+	if t.N == 0 {
+		return false
+	}
+	if t.sorted {
+		// A list sorted from the worst is a heap
+		for i, j := 0, len(t.List)-1; i < j; i, j = i+1, j-1 {
+			t.swap(i, j)
+		}
+		t.sorted = false
+	}
+	if t.index != nil {
+		if i, ok := t.index[key]; ok {
+			if !t.worse(t.List[i], scoredDatum) {
+				return false
+			}
+			// A better datum is further from the worst at the root
+			t.List[i] = scoredDatum
+			t.down(i)
+			return true
+		}
+	}
+	if uint32(len(t.List)) < t.N {
+		t.List = append(t.List, scoredDatum)
+		if t.index != nil {
+			t.keys = append(t.keys, key)
+			t.index[key] = len(t.List) - 1
+		}
+		t.up(len(t.List) - 1)
+		return true
+	}
+	if t.worse(t.List[0], scoredDatum) {
+		t.List[0] = scoredDatum
+		if t.index != nil {
+			delete(t.index, t.keys[0])
+			t.keys[0] = key
+			t.index[key] = 0
+		}
+		t.down(0)
+		return true
+	}
+	return false
+}
+
+// Accepts is true if a datum with score may be added
+func (t *TopK) Accepts(score float64) bool {
+	if uint32(len(t.List)) < t.N {
+		return true
+	}
+	if t.N == 0 {
+		return false
+	}
+	worst := t.Worst().Score
+	if t.TieBreak && score == worst {
+		return true // the key decides
+	}
+	return (t.HigherIsBetter && score > worst) || (!t.HigherIsBetter && score < worst)
+}
+
+// Worst returns the worst datum in the list, nil if the list is empty
+func (t *TopK) Worst() *pb.ScoredDatum {
+	if len(t.List) == 0 {
+		return nil
+	}
+	if t.sorted {
+		return t.List[len(t.List)-1]
+	}
+	return t.List[0]
+}
+
+// Sort orders List from the best to the worst datum
+func (t *TopK) Sort() {
+	if t.sorted {
+		return
+	}
+	sort.Sort(bestFirstTopK{t})
+	t.sorted = true
+}
+
+// Sorted returns a copy of the list ordered from the best to the worst datum
+func (t *TopK) Sorted() []*pb.ScoredDatum {
+	t.Sort()
+	list := make([]*pb.ScoredDatum, len(t.List))
+	copy(list, t.List)
+	return list
+}
+
+// worse is true if a has a worse score than b
+func (t *TopK) worse(a, b *pb.ScoredDatum) bool {
+	if t.TieBreak && a.Score == b.Score {
+		return compareKeys(a.GetDatum(), b.GetDatum()) > 0
+	}
+	if t.HigherIsBetter {
+		return a.Score < b.Score
+	}
+	return a.Score > b.Score
+}
+
+// compareKeys compares the encoded keys of datums, datums that can not be encoded are equal
+func compareKeys(a, b *pb.Datum) int {
+	aKey, err := GetKeyAsBytes(a)
+	if err != nil {
+		return 0
+	}
+	bKey, err := GetKeyAsBytes(b)
+	if err != nil {
+		return 0
+	}
+	return bytes.Compare(aKey, bKey)
+}
+
+func (t *TopK) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !t.worse(t.List[i], t.List[parent]) {
+			return
+		}
+		t.List[i], t.List[parent] = t.List[parent], t.List[i]
+		i = parent
+	}
+}
+
+func (t *TopK) down(i int) {
+	n := len(t.List)
+	for {
+		worst := i
+		if left := 2*i + 1; left < n && t.worse(t.List[left], t.List[worst]) {
+			worst = left
+		}
+		if right := 2*i + 2; right < n && t.worse(t.List[right], t.List[worst]) {
+			worst = right
+		}
+		if worst == i {
+			return
+		}
+		t.swap(i, worst)
+		i = worst
+	}
+}
+
+func (t *TopK) swap(i, j int) {
+	t.List[i], t.List[j] = t.List[j], t.List[i]
+	if t.index != nil {
+		t.keys[i], t.keys[j] = t.keys[j], t.keys[i]
+		t.index[t.keys[i]] = i
+		t.index[t.keys[j]] = j
+	}
+}
+
+// bestFirstTopK sorts a top list from the best datum keeping the keys in place
+type bestFirstTopK struct {
+	t *TopK
+}
+
+func (s bestFirstTopK) Len() int           { return len(s.t.List) }
+func (s bestFirstTopK) Less(i, j int) bool { return s.t.worse(s.t.List[j], s.t.List[i]) }
+func (s bestFirstTopK) Swap(i, j int)      { s.t.swap(i, j) }
@@ -0,0 +1,136 @@
+package data_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	data "github.com/bgokden/veri/data"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/bgokden/veri/veriservice"
+)
+
+const benchmarkItems = 20000
+
+func benchmarkScoredDatums() []*pb.ScoredDatum {
+	random := rand.New(rand.NewSource(42))
+	scoredDatumList := make([]*pb.ScoredDatum, benchmarkItems)
+	for i := range scoredDatumList {
+		score := random.Float64()
+		scoredDatumList[i] = &pb.ScoredDatum{
+			Datum:       data.NewDatum([]float32{float32(i), 1}, 2, 0, 1, 0, nil, nil, 0),
+			Score:       score,
+			VectorScore: score,
+		}
+	}
+	return scoredDatumList
+}
+
+func TestTopK(t *testing.T) {
+	scoredDatumList := benchmarkScoredDatums()
+	for _, higherIsBetter := range []bool{true, false} {
+		scores := make([]float64, len(scoredDatumList))
+		for i, scoredDatum := range scoredDatumList {
+			scores[i] = scoredDatum.Score
+		}
+		if higherIsBetter {
+			sort.Sort(sort.Reverse(sort.Float64Slice(scores)))
+		} else {
+			sort.Float64s(scores)
+		}
+		topK := data.NewTopK(100, higherIsBetter)
+		half := len(scoredDatumList) / 2
+		for _, scoredDatum := range scoredDatumList[:half] {
+			topK.Push(scoredDatum)
+		}
+		topK.Sort()
+		// Pushing to a sorted list continues with the heap
+		for _, scoredDatum := range scoredDatumList[half:] {
+			topK.Push(scoredDatum)
+		}
+		assert.False(t, topK.Accepts(scores[100]))
+		assert.True(t, topK.Accepts(scores[0]))
+		result := topK.Sorted()
+		assert.Equal(t, 100, len(result))
+		for i, scoredDatum := range result {
+			assert.Equal(t, scores[i], scoredDatum.Score)
+		}
+		assert.Equal(t, scores[99], topK.Worst().Score)
+	}
+	empty := data.NewTopK(0, true)
+	assert.False(t, empty.Push(scoredDatumList[0]))
+	assert.Nil(t, empty.Worst())
+}
+
+func TestKeyedTopK(t *testing.T) {
+	scoredDatum := func(key int, score float64) *pb.ScoredDatum {
+		return &pb.ScoredDatum{
+			Datum:       data.NewDatum([]float32{float32(key), 1}, 2, 0, 1, 0, nil, nil, 0),
+			Score:       score,
+			VectorScore: score,
+		}
+	}
+	topK := data.NewKeyedTopK(3, false)
+	assert.True(t, topK.PushKey("a", scoredDatum(0, 5)))
+	assert.True(t, topK.PushKey("a", scoredDatum(0, 3)))
+	assert.False(t, topK.PushKey("a", scoredDatum(0, 4)))
+	assert.True(t, topK.PushKey("b", scoredDatum(1, 4)))
+	result := topK.Sorted()
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, 3.0, result[0].Score)
+	assert.Equal(t, 4.0, result[1].Score)
+	// Evicting the worst key allows it to come back
+	assert.True(t, topK.PushKey("c", scoredDatum(2, 1)))
+	assert.True(t, topK.PushKey("d", scoredDatum(3, 2)))
+	assert.True(t, topK.PushKey("b", scoredDatum(1, 0)))
+	result = topK.Sorted()
+	assert.Equal(t, []float64{0, 1, 2}, []float64{result[0].Score, result[1].Score, result[2].Score})
+
+	config := data.DefaultSearchConfig()
+	config.Limit = 3
+	config.HigherIsBetter = false
+	aggregator := data.NewAggrator(config, false, nil)
+	aggregator.Insert(scoredDatum(0, 5))
+	aggregator.Insert(scoredDatum(0, 3))
+	aggregator.Insert(scoredDatum(1, 4))
+	result = aggregator.Result()
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, 3.0, result[0].Score)
+	assert.Equal(t, 4.0, result[1].Score)
+}
+
+func BenchmarkCollectorInsert(b *testing.B) {
+	scoredDatumList := benchmarkScoredDatums()
+	query := data.NewDatum([]float32{0, 1}, 2, 0, 1, 0, nil, nil, 0)
+	for _, limit := range []uint32{10, 100, 1000, 10000} {
+		b.Run(fmt.Sprintf("Limit%v", limit), func(b *testing.B) {
+			config := data.DefaultSearchConfig()
+			config.Limit = limit
+			for n := 0; n < b.N; n++ {
+				collector := data.NewCollector(query, config)
+				for _, scoredDatum := range scoredDatumList {
+					collector.Insert(scoredDatum)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkAggregatorInsert(b *testing.B) {
+	scoredDatumList := benchmarkScoredDatums()
+	for _, limit := range []uint32{10, 100, 1000, 10000} {
+		b.Run(fmt.Sprintf("Limit%v", limit), func(b *testing.B) {
+			config := data.DefaultSearchConfig()
+			config.Limit = limit
+			for n := 0; n < b.N; n++ {
+				aggregator := data.NewAggrator(config, false, nil)
+				for _, scoredDatum := range scoredDatumList {
+					aggregator.Insert(scoredDatum)
+				}
+				aggregator.Result()
+			}
+		})
+	}
+}
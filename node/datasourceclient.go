@@ -3,6 +3,7 @@ package node
 import (
 	"context"
 	"errors"
+	"io"
 	"log"
 	"sync"
 
@@ -39,7 +40,7 @@ type DataSourceClient struct {
 // 	return client, conn, nil
 // }
 
-func (dcs *DataSourceClient) StreamSearch(datum *pb.Datum, scoredDatumStream chan<- *pb.ScoredDatum, queryWaitGroup *sync.WaitGroup, config *pb.SearchConfig) error {
+func (dcs *DataSourceClient) StreamSearch(datum *pb.Datum, scoredDatumStream chan<- *pb.ScoredDatum, queryWaitGroup *sync.WaitGroup, config *pb.SearchConfig, searchContext *pb.SearchContext) error {
 	defer queryWaitGroup.Done()
 	conn := dcs.ConnectionCache.Get(dcs.IdOfPeer)
 	if conn == nil {
@@ -48,8 +49,9 @@ func (dcs *DataSourceClient) StreamSearch(datum *pb.Datum, scoredDatumStream cha
 	defer dcs.ConnectionCache.Put(conn)
 	client := conn.Client
 	searchRequest := &pb.SearchRequest{
-		Datum:  []*pb.Datum{datum},
-		Config: config,
+		Datum:   []*pb.Datum{datum},
+		Config:  config,
+		Context: searchContext,
 	}
 	stream, err := client.SearchStream(context.Background(), searchRequest)
 	if err != nil {
@@ -57,16 +59,20 @@ func (dcs *DataSourceClient) StreamSearch(datum *pb.Datum, scoredDatumStream cha
 	}
 	for {
 		protoScoredDatum, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
 		if err != nil {
 			// log.Printf("Error: (%v)", err)
-			break
+			return err // the source did not answer completely
 		}
 		// log.Printf("Received From:  %v for Score: %v Label: %v", dcs.Ids, protoScoredDatum.Score, string(protoScoredDatum.GetDatum().GetValue().GetLabel()))
 		scoredDatumStream <- protoScoredDatum
 	}
-	return err
 }
 
+// Insert upserts datum to the peer
+// It returns data.ErrStaleVersion if the peer has a newer version and data.ErrDeleted if the peer deleted it
 func (dcs *DataSourceClient) Insert(datum *pb.Datum, config *pb.InsertConfig) error {
 	conn := dcs.ConnectionCache.Get(dcs.IdOfPeer)
 	if conn == nil {
@@ -79,8 +85,111 @@ func (dcs *DataSourceClient) Insert(datum *pb.Datum, config *pb.InsertConfig) er
 		Datum:    datum,
 		DataName: dcs.Name,
 	}
-	_, err := client.Insert(context.Background(), request)
-	return err
+	response, err := client.Upsert(context.Background(), request)
+	if err != nil {
+		return err
+	}
+	if !response.GetApplied() {
+		if response.GetError() == data.ErrDeleted.Error() {
+			return data.ErrDeleted
+		}
+		return data.ErrStaleVersion
+	}
+	return nil
+}
+
+func (dcs *DataSourceClient) InsertBatch(datumList []*pb.InsertDatumWithConfig) ([]*pb.InsertStatus, error) {
+	conn := dcs.ConnectionCache.Get(dcs.IdOfPeer)
+	if conn == nil {
+		return nil, errors.New("Connection failure")
+	}
+	defer dcs.ConnectionCache.Put(conn)
+	client := conn.Client
+	request := &pb.InsertBatchRequest{
+		DataName: dcs.Name,
+		Datum:    datumList,
+	}
+	response, err := client.InsertBatch(context.Background(), request)
+	if err != nil {
+		return nil, err
+	}
+	return response.GetStatus(), nil
+}
+
+// MultiGet asks only the peer, it does not forward the request to its sources
+func (dcs *DataSourceClient) MultiGet(keyList []*pb.DatumKey) ([]*pb.GetResponse, error) {
+	conn := dcs.ConnectionCache.Get(dcs.IdOfPeer)
+	if conn == nil {
+		return nil, errors.New("Connection failure")
+	}
+	defer dcs.ConnectionCache.Put(conn)
+	client := conn.Client
+	request := &pb.MultiGetRequest{
+		DataName: dcs.Name,
+		Key:      keyList,
+		Local:    true,
+	}
+	response, err := client.MultiGet(context.Background(), request)
+	if err != nil {
+		return nil, err
+	}
+	return response.GetResult(), nil
+}
+
+func (dcs *DataSourceClient) Remove(request *pb.DeleteRequest) (uint64, error) {
+	conn := dcs.ConnectionCache.Get(dcs.IdOfPeer)
+	if conn == nil {
+		return 0, errors.New("Connection failure")
+	}
+	defer dcs.ConnectionCache.Put(conn)
+	client := conn.Client
+	// The request is shared by every source
+	peerRequest := &pb.DeleteRequest{
+		DataName:     dcs.Name,
+		Datum:        request.GetDatum(),
+		Filters:      request.GetFilters(),
+		GroupFilters: request.GetGroupFilters(),
+		Config:       request.GetConfig(),
+	}
+	response, err := client.Delete(context.Background(), peerRequest)
+	if err != nil {
+		return 0, err
+	}
+	return response.GetDeleted(), nil
+}
+
+// StreamData streams the data of the peer to datumStream, the stream is cancelled when ctx is done
+func (dcs *DataSourceClient) StreamData(ctx context.Context, datumStream chan<- *pb.Datum, config *pb.StreamConfig) error {
+	conn := dcs.ConnectionCache.Get(dcs.IdOfPeer)
+	if conn == nil {
+		return errors.New("Connection failure")
+	}
+	defer dcs.ConnectionCache.Put(conn)
+	client := conn.Client
+	request := &pb.GetDataRequest{
+		Name:   dcs.Name,
+		Config: config,
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // stops the peer if the stream is not read to the end
+	stream, err := client.DataStream(ctx, request)
+	if err != nil {
+		return err
+	}
+	for {
+		datum, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		select {
+		case datumStream <- datum:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
 }
 
 func (dcs *DataSourceClient) GetDataInfo() *pb.DataInfo {
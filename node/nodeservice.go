@@ -4,18 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"strings"
 	"time"
 
 	"github.com/bgokden/go-cache"
+	data "github.com/bgokden/veri/data"
 	"github.com/bgokden/veri/state"
 	pb "github.com/bgokden/veri/veriservice"
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	grpcPeer "google.golang.org/grpc/peer"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 // type VeriServiceServer interface {
@@ -29,7 +34,14 @@ import (
 // }
 
 func (n *Node) Search(ctx context.Context, searchRequest *pb.SearchRequest) (*pb.SearchResponse, error) {
-	return nil, nil
+	result, metadata, err := n.search(searchRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SearchResponse{
+		Result:   result,
+		Metadata: metadata,
+	}, nil
 }
 
 func (n *Node) Insert(ctx context.Context, insertionRequest *pb.InsertionRequest) (*pb.InsertionResponse, error) {
@@ -50,6 +62,202 @@ func (n *Node) Insert(ctx context.Context, insertionRequest *pb.InsertionRequest
 	return &pb.InsertionResponse{Code: 0}, nil
 }
 
+func (n *Node) InsertBatch(ctx context.Context, insertBatchRequest *pb.InsertBatchRequest) (*pb.InsertBatchResponse, error) {
+	if state.Drain {
+		return nil, errors.New("Node is in drain mode")
+	}
+	dt, err := n.Dataset.Get(insertBatchRequest.GetDataName())
+	if err != nil {
+		return nil, err
+	}
+	datumList := insertBatchRequest.GetDatum()
+	statusList := make([]*pb.InsertStatus, 0, len(datumList))
+	for start := 0; start < len(datumList); start += data.InsertBatchSize {
+		end := start + data.InsertBatchSize
+		if end > len(datumList) {
+			end = len(datumList)
+		}
+		batchStatusList, err := dt.InsertBatch(datumList[start:end])
+		if err != nil {
+			statusList = append(statusList, failedStatusList(len(datumList)-start, err)...)
+			return &pb.InsertBatchResponse{Code: 1, Status: statusList}, nil
+		}
+		statusList = append(statusList, batchStatusList...)
+	}
+	return &pb.InsertBatchResponse{Code: 0, Status: statusList}, nil
+}
+
+// failedStatusList returns n statuses of datums that are not inserted because of err
+func failedStatusList(n int, err error) []*pb.InsertStatus {
+	statusList := make([]*pb.InsertStatus, n)
+	for i := range statusList {
+		statusList[i] = &pb.InsertStatus{Error: err.Error()}
+	}
+	return statusList
+}
+
+// InsertStream applies streamed datums in batches of data.InsertBatchSize
+// A batch is also applied when the data name changes, statuses are in the order of the stream
+// If a batch fails, the statuses so far and the failed batch are sent with Code 1 and the stream is closed
+func (n *Node) InsertStream(stream pb.VeriService_InsertStreamServer) error {
+	if state.Drain {
+		return errors.New("Node is in drain mode")
+	}
+	statusList := make([]*pb.InsertStatus, 0)
+	batch := make([]*pb.InsertDatumWithConfig, 0, data.InsertBatchSize)
+	name := ""
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		dt, err := n.Dataset.Get(name)
+		if err == nil {
+			var batchStatusList []*pb.InsertStatus
+			batchStatusList, err = dt.InsertBatch(batch)
+			statusList = append(statusList, batchStatusList...)
+		}
+		if err != nil {
+			statusList = append(statusList, failedStatusList(len(batch), err)...)
+		}
+		batch = batch[:0]
+		return err
+	}
+	for {
+		insertionRequest, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if insertionRequest.GetDataName() != name || len(batch) >= data.InsertBatchSize {
+			err = flush()
+			if err != nil {
+				return stream.SendAndClose(&pb.InsertBatchResponse{Code: 1, Status: statusList})
+			}
+			name = insertionRequest.GetDataName()
+		}
+		batch = append(batch, &pb.InsertDatumWithConfig{
+			Datum:  insertionRequest.GetDatum(),
+			Config: insertionRequest.GetConfig(),
+		})
+	}
+	code := int32(0)
+	if flush() != nil {
+		code = 1
+	}
+	return stream.SendAndClose(&pb.InsertBatchResponse{Code: code, Status: statusList})
+}
+
+func (n *Node) Get(ctx context.Context, getRequest *pb.GetRequest) (*pb.GetResponse, error) {
+	if getRequest.GetKey() == nil {
+		return nil, status.Error(codes.InvalidArgument, "Key is missing")
+	}
+	response, err := n.MultiGet(ctx, &pb.MultiGetRequest{
+		DataName: getRequest.GetDataName(),
+		Key:      []*pb.DatumKey{getRequest.GetKey()},
+		Local:    getRequest.GetLocal(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.GetResult()[0], nil
+}
+
+// MultiGet returns a result for every key in order, a missing key is not found
+func (n *Node) MultiGet(ctx context.Context, multiGetRequest *pb.MultiGetRequest) (*pb.MultiGetResponse, error) {
+	dt, err := n.Dataset.GetNoCreate(multiGetRequest.GetDataName())
+	if err != nil {
+		return nil, err
+	}
+	var result []*pb.GetResponse
+	if multiGetRequest.GetLocal() {
+		result, err = dt.MultiGet(multiGetRequest.GetKey())
+	} else {
+		result, err = dt.AggregatedMultiGet(multiGetRequest.GetKey())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pb.MultiGetResponse{Result: result}, nil
+}
+
+func (n *Node) ListByField(ctx context.Context, fieldQuery *pb.FieldQuery) (*pb.FieldQueryResponse, error) {
+	dt, err := n.Dataset.GetNoCreate(fieldQuery.GetDataName())
+	if err != nil {
+		return nil, err
+	}
+	if len(fieldQuery.GetValue()) == 0 {
+		counts, err := dt.CountByField(fieldQuery.GetField())
+		if err != nil {
+			return nil, err
+		}
+		total := uint64(0)
+		for _, count := range counts {
+			total += count.Count
+		}
+		return &pb.FieldQueryResponse{Count: total, Values: counts}, nil
+	}
+	datumList, count, err := dt.ListByField(fieldQuery.GetField(), fieldQuery.GetValue(), int(fieldQuery.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.FieldQueryResponse{Count: count, Datum: datumList}, nil
+}
+
+func (n *Node) Upsert(ctx context.Context, insertionRequest *pb.InsertionRequest) (*pb.UpsertResponse, error) {
+	if state.Drain {
+		return nil, errors.New("Node is in drain mode")
+	}
+	config := insertionRequest.GetConfig()
+	datum := insertionRequest.GetDatum()
+	name := insertionRequest.GetDataName()
+	dt, err := n.Dataset.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := dt.Upsert(datum, config)
+	if err != nil && !applied && err != data.ErrDeleted {
+		return nil, err
+	}
+	response := &pb.UpsertResponse{Code: 0, Applied: applied}
+	if err != nil {
+		// Rejected by a tombstone, or applied locally with a replication error
+		response.Code = 1
+		response.Error = err.Error()
+	}
+	return response, nil
+}
+
+func (n *Node) Delete(ctx context.Context, deleteRequest *pb.DeleteRequest) (*pb.DeleteResponse, error) {
+	if state.Drain {
+		return nil, errors.New("Node is in drain mode")
+	}
+	dt, err := n.Dataset.GetNoCreate(deleteRequest.GetDataName())
+	if err != nil {
+		return nil, err
+	}
+	if len(deleteRequest.GetDatum()) == 0 {
+		// Deletes by filters are forwarded to every source, a node applies one once
+		if deleteRequest.Config == nil {
+			deleteRequest.Config = &pb.DeleteConfig{}
+		}
+		uid, isNew, err := n.checkQueryUUID(deleteRequest.Config.GetUuid())
+		if err != nil {
+			return nil, err
+		}
+		if !isNew {
+			return &pb.DeleteResponse{Code: 0}, nil
+		}
+		deleteRequest.Config.Uuid = uid
+	}
+	deleted, err := dt.Remove(deleteRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.DeleteResponse{Code: 0, Deleted: deleted}, nil
+}
+
 func (n *Node) Join(ctx context.Context, joinRequest *pb.JoinRequest) (*pb.JoinResponse, error) {
 	peer := joinRequest.GetPeer()
 	n.AddPeerElement(peer)
@@ -78,30 +286,55 @@ func (n *Node) Join(ctx context.Context, joinRequest *pb.JoinRequest) (*pb.JoinR
 }
 
 func (n *Node) DataStream(getDataRequest *pb.GetDataRequest, stream pb.VeriService_DataStreamServer) error {
-	return errors.New("Not implemented")
-	// name := getDataRequest.GetName()
-	// dt, err := n.Dataset.Get(name)
-	// if err != nil {
-	// 	return err
-	// }
-	// datumStreamAll := make(chan *pb.Datum, 100)
-	// go func() {
-	// 	for datum := range datumStreamAll {
-	// 		// log.Printf("Send label: %v\n", string(datum.Value.Label))
-	// 		stream.Send(datum)
-	// 	}
-	// }()
-	// err = dt.StreamAll(datumStreamAll)
-	// if err != nil {
-	// 	return err
-	// }
-	// close(datumStreamAll)
-	// for datum := range datumStreamAll {
-	// 	// log.Printf("Flush - Send label: %v\n", string(datum.Value.Label))
-	// 	stream.Send(datum)
-	// }
-	// // log.Printf("DataStream finished\n")
-	// return nil
+	name := getDataRequest.GetName()
+	config := getDataRequest.GetConfig()
+	if config == nil {
+		config = &pb.StreamConfig{}
+	}
+	if config.GetCluster() {
+		uid, isNew, err := n.checkQueryUUID(config.GetUuid())
+		if err != nil {
+			return err
+		}
+		if !isNew {
+			return nil
+		}
+		config.Uuid = uid
+	}
+	dt, err := n.Dataset.GetNoCreate(name)
+	if err != nil {
+		return err
+	}
+	// The scan and the peer streams stop when the client goes away or a send fails
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	datumStream := make(chan *pb.Datum, 100)
+	sendDone := make(chan error)
+	go func() {
+		var sendErr error
+		for datum := range datumStream {
+			if sendErr == nil {
+				// log.Printf("Send label: %v\n", string(datum.Value.Label))
+				sendErr = stream.Send(datum)
+				if sendErr != nil {
+					cancel()
+				}
+			}
+		}
+		sendDone <- sendErr
+	}()
+	if config.GetCluster() {
+		err = dt.AggregatedStreamData(ctx, datumStream, config)
+	} else {
+		err = dt.StreamData(ctx, datumStream, config)
+	}
+	close(datumStream)
+	sendErr := <-sendDone
+	if sendErr != nil {
+		return sendErr
+	}
+	// log.Printf("DataStream finished\n")
+	return err
 }
 
 func (n *Node) GetDataInfo(ctx context.Context, getDataRequest *pb.GetDataRequest) (*pb.DataInfo, error) {
@@ -116,34 +349,16 @@ func (n *Node) GetDataInfo(ctx context.Context, getDataRequest *pb.GetDataReques
 	return nil, nil
 }
 
+// NextPageTokenKey is the trailer of SearchStream with the token of the next page
+const NextPageTokenKey = "next-page-token"
+
 func (n *Node) SearchStream(searchRequest *pb.SearchRequest, stream pb.VeriService_SearchStreamServer) error {
-	config := searchRequest.GetConfig()
-	uid := config.GetUuid()
-	if uid == "" {
-		uid, err := uuid.NewRandom()
-		if err != nil {
-			return err
-		}
-		config.Uuid = uid.String()
-	} else {
-		err := n.QueryUUIDCache.Add(uid, true, cache.DefaultExpiration)
-		if err != nil {
-			if strings.Contains(err.Error(), "already exists") {
-				return nil
-			}
-			return err
-		}
-	}
-	aData, err := n.Dataset.GetNoCreate(config.GetDataName())
+	result, searchMetadata, err := n.search(searchRequest)
 	if err != nil {
 		return err
 	}
-	datumList := searchRequest.GetDatum()
-	searchConfig := searchRequest.GetConfig()
-	searchContext := searchRequest.GetContext()
-	result, err := aData.MultiAggregatedSearch(datumList, searchConfig, searchContext)
-	if err != nil {
-		return err
+	if token := searchMetadata.GetNextPageToken(); token != "" {
+		stream.SetTrailer(metadata.Pairs(NextPageTokenKey, token))
 	}
 	// log.Printf("SearchStream: finished with len(%v)", len(result))
 	for _, e := range result {
@@ -154,6 +369,50 @@ func (n *Node) SearchStream(searchRequest *pb.SearchRequest, stream pb.VeriServi
 	return nil
 }
 
+// checkQueryUUID registers uid of a distributed query, a new uid is generated if it is empty
+// isNew is false if the query is already seen by this node
+func (n *Node) checkQueryUUID(uid string) (string, bool, error) {
+	if uid == "" {
+		newUID, err := uuid.NewRandom()
+		if err != nil {
+			return "", false, err
+		}
+		uid = newUID.String()
+	}
+	err := n.QueryUUIDCache.Add(uid, true, cache.DefaultExpiration)
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			return uid, false, nil
+		}
+		return uid, false, err
+	}
+	return uid, true, nil
+}
+
+// search is the common path of Search and SearchStream
+// A request with an already seen uuid returns an empty result to avoid loops
+func (n *Node) search(searchRequest *pb.SearchRequest) ([]*pb.ScoredDatum, *pb.SearchMetadata, error) {
+	config := searchRequest.GetConfig()
+	if config == nil {
+		return nil, nil, errors.New("Search config is missing")
+	}
+	uid, isNew, err := n.checkQueryUUID(config.GetUuid())
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isNew {
+		return nil, &pb.SearchMetadata{}, nil
+	}
+	config.Uuid = uid
+	aData, err := n.Dataset.GetNoCreate(config.GetDataName())
+	if err != nil {
+		return nil, nil, err
+	}
+	datumList := searchRequest.GetDatum()
+	searchContext := searchRequest.GetContext()
+	return aData.MultiAggregatedSearch(datumList, config, searchContext)
+}
+
 func (n *Node) Listen() error {
 	lis, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", n.Port))
 	if err != nil {
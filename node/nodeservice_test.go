@@ -1,6 +1,7 @@
 package node_test
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -9,7 +10,11 @@ import (
 	"testing"
 	"time"
 
+	data "github.com/bgokden/veri/data"
 	node "github.com/bgokden/veri/node"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/bgokden/veri/veriservice"
 )
 
 func randomPort() uint32 {
@@ -72,3 +77,40 @@ func TestNode(t *testing.T) {
 		log.Printf("%v:\n", peerFromPeer.AddressList)
 	}
 }
+
+func TestNodeDeleteByFiltersOnce(t *testing.T) {
+	dir, err := ioutil.TempDir("", "node")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	node0 := node.NewNode(&node.NodeConfig{Folder: dir})
+	dt, err := node0.Dataset.GetOrCreateIfNotExists(&pb.DataConfig{Name: "deletes", TargetN: 1000})
+	assert.Nil(t, err)
+	insert := func(i int) {
+		label := []byte(fmt.Sprintf(`{"even": true, "i": %v}`, i))
+		assert.Nil(t, dt.Insert(data.NewDatum([]float32{float32(i), 1}, 2, 0, 1, 0, []byte("{}"), label, 0), nil))
+	}
+	request := func(uid string) *pb.DeleteRequest {
+		return &pb.DeleteRequest{
+			DataName: "deletes",
+			Filters:  []string{"even"},
+			Config:   &pb.DeleteConfig{Uuid: uid},
+		}
+	}
+
+	insert(0)
+	response, err := node0.Delete(context.Background(), request("delete-0"))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), response.Deleted)
+
+	// A delete arriving again from another peer is not applied twice
+	insert(2)
+	response, err = node0.Delete(context.Background(), request("delete-0"))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), response.Deleted)
+	response, err = node0.Delete(context.Background(), request(""))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), response.Deleted)
+}
@@ -128,51 +128,148 @@ func (ma *MemoliArena) Free(ptr unsafe.Pointer) {
 	ma.Reuseables.Push(index)
 }
 
+// Contains is true if ptr is a block of the arena
+func (ma *MemoliArena) Contains(ptr unsafe.Pointer) bool {
+	return uintptr(ptr) >= ma.StartPointer && uintptr(ptr) <= ma.StartPointer+ma.MaxSize
+}
+
+// memoliArenaSize is the size of an arena, a size class gets another arena when its arenas are full
+const memoliArenaSize = 1 << 24
+
+// Memoli allocates blocks from mmap backed arenas, size classes have their own arenas
+// Size classes are powers of two starting from BucketSize, an arena has at most Length blocks
+// Blocks are not scanned by the garbage collector so they should not keep Go pointers
 type Memoli struct {
 	ArenaMap   sync.Map
 	Length     int
 	BucketSize uintptr
+	arenaMutex sync.Mutex
+}
+
+// memoliSizeClass is the list of arenas of a size class
+// Arenas before next are full
+type memoliSizeClass struct {
+	sync.Mutex
+	arenas []*MemoliArena
+	next   int
 }
 
 func NewGlobalMemoli() *Memoli {
 	return &Memoli{
 		Length:     1e+6,
-		BucketSize: uintptr(256),
+		BucketSize: uintptr(32),
+	}
+}
+
+// SizeClass is the block size that size is allocated in
+func (m *Memoli) SizeClass(size uintptr) uintptr {
+	class := m.BucketSize
+	for class < size {
+		class <<= 1
 	}
+	return class
 }
 
 func (m *Memoli) ArenaKey(size uintptr) uintptr {
-	return size % m.BucketSize
+	return m.SizeClass(size)
 }
 
+// getSizeClass returns the arenas of the size class, they are created on first use
+func (m *Memoli) getSizeClass(size uintptr) *memoliSizeClass {
+	key := m.ArenaKey(size)
+	if classInterface, ok := m.ArenaMap.Load(key); ok {
+		return classInterface.(*memoliSizeClass)
+	}
+	m.arenaMutex.Lock()
+	defer m.arenaMutex.Unlock()
+	if classInterface, ok := m.ArenaMap.Load(key); ok {
+		return classInterface.(*memoliSizeClass)
+	}
+	sizeClass := &memoliSizeClass{}
+	m.ArenaMap.Store(key, sizeClass)
+	return sizeClass
+}
+
+// New returns a block of the size class of size
+// A new arena is mapped when every arena of the class is full, nil is returned if the class is too large for an arena
 func (m *Memoli) New(size uintptr) unsafe.Pointer {
-	maInterface, _ := m.ArenaMap.LoadOrStore(m.ArenaKey(size), getNewMemoliArena(size, m.Length))
-	if maInterface == nil {
-		return nil
+	key := m.ArenaKey(size)
+	length := m.Length
+	if uintptr(length)*key > memoliArenaSize {
+		length = int(memoliArenaSize / key)
 	}
-	if ma, ok := maInterface.(*MemoliArena); ok {
-		return ma.New()
+	if length < 2 {
+		return nil // too large for an arena
 	}
-	return nil
+	sizeClass := m.getSizeClass(size)
+	sizeClass.Lock()
+	defer sizeClass.Unlock()
+	for ; sizeClass.next < len(sizeClass.arenas); sizeClass.next++ {
+		if ptr := sizeClass.arenas[sizeClass.next].New(); ptr != nil {
+			return ptr
+		}
+	}
+	ma := getNewMemoliArena(key, length)
+	if ma == nil {
+		return nil
+	}
+	sizeClass.arenas = append(sizeClass.arenas, ma)
+	return ma.New()
 }
 
+// Free returns a block of the size class of size to its arena, pointers out of the arenas are ignored
 func (m *Memoli) Free(ptr unsafe.Pointer, size uintptr) {
-	// size := unsafe.Sizeof(ptr)
-	// log.Printf("Size: %v\n", size)
-	if maInterface, ok := m.ArenaMap.Load(m.ArenaKey(size)); ok {
-		if ma, ok2 := maInterface.(*MemoliArena); ok2 {
+	classInterface, ok := m.ArenaMap.Load(m.ArenaKey(size))
+	if !ok {
+		return
+	}
+	sizeClass := classInterface.(*memoliSizeClass)
+	sizeClass.Lock()
+	defer sizeClass.Unlock()
+	for i, ma := range sizeClass.arenas {
+		if ma.Contains(ptr) {
 			ma.Free(ptr)
+			if i < sizeClass.next {
+				sizeClass.next = i
+			}
+			return
 		}
 	}
 }
 
+// NewBytes returns a byte slice of length size, capacity is the size class
+// It falls back to the Go heap if the size class is too large for an arena
+func (m *Memoli) NewBytes(size int) []byte {
+	class := m.SizeClass(uintptr(size))
+	if class < memoliArenaSize {
+		if ptr := m.New(class); ptr != nil {
+			return (*[memoliArenaSize]byte)(ptr)[:size:class]
+		}
+	}
+	return make([]byte, size)
+}
+
+// FreeBytes releases a slice returned by NewBytes, slices on the Go heap are left to the garbage collector
+func (m *Memoli) FreeBytes(b []byte) {
+	if cap(b) == 0 {
+		return
+	}
+	m.Free(unsafe.Pointer(&b[:1][0]), uintptr(cap(b)))
+}
+
 func (m *Memoli) Close() error {
 	m.ArenaMap.Range(func(_, value interface{}) bool {
-		if ma, ok := value.(*MemoliArena); ok {
-			err := ma.Close(true)
-			if err != nil {
-				log.Printf("Error %v\n", err) // There is not much to do
+		if sizeClass, ok := value.(*memoliSizeClass); ok {
+			sizeClass.Lock()
+			for _, ma := range sizeClass.arenas {
+				err := ma.Close(true)
+				if err != nil {
+					log.Printf("Error %v\n", err) // There is not much to do
+				}
 			}
+			sizeClass.arenas = nil
+			sizeClass.next = 0
+			sizeClass.Unlock()
 		}
 		return true
 	})
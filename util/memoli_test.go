@@ -97,3 +97,47 @@ func TestGlobalMemoli(t *testing.T) {
 		fmt.Printf("%v -> %p = _%v_\n", i, slicePtr, *slicePtr)
 	}
 }
+
+func TestMemoliSizeClass(t *testing.T) {
+	m := util.NewGlobalMemoli()
+	assert.Equal(t, uintptr(32), m.SizeClass(1))
+	assert.Equal(t, uintptr(32), m.SizeClass(32))
+	assert.Equal(t, uintptr(64), m.SizeClass(33))
+	assert.Equal(t, uintptr(16384), m.SizeClass(10000))
+
+	b := m.NewBytes(100)
+	assert.Equal(t, 100, len(b))
+	assert.Equal(t, 128, cap(b))
+	copy(b, []byte("hello"))
+	ptr := &b[0]
+	m.FreeBytes(b)
+	// Freed block is reused by the same size class
+	b2 := m.NewBytes(120)
+	assert.Equal(t, ptr, &b2[0])
+	m.FreeBytes(b2)
+	// Heap slices are ignored
+	m.FreeBytes(make([]byte, 128))
+	assert.Nil(t, m.Close())
+}
+
+func TestMemoliArenaGrowth(t *testing.T) {
+	m := &util.Memoli{
+		Length:     4,
+		BucketSize: uintptr(32),
+	}
+	// A full arena is followed by another one of the same size class
+	blocks := make([][]byte, 10)
+	seen := make(map[*byte]bool)
+	for i := range blocks {
+		blocks[i] = m.NewBytes(20)
+		assert.Equal(t, 32, cap(blocks[i]))
+		assert.False(t, seen[&blocks[i][0]])
+		seen[&blocks[i][0]] = true
+	}
+	// A block freed in the first arena is reused before new blocks
+	ptr := &blocks[1][0]
+	m.FreeBytes(blocks[1])
+	b := m.NewBytes(32)
+	assert.Equal(t, ptr, &b[0])
+	assert.Nil(t, m.Close())
+}
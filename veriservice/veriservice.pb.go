@@ -106,11 +106,26 @@ type SearchConfig struct {
 	CacheDuration      uint64   `protobuf:"varint,6,opt,name=cacheDuration,proto3" json:"cacheDuration,omitempty"`
 	DataName           string   `protobuf:"bytes,7,opt,name=dataName,proto3" json:"dataName,omitempty"`
 	GroupLimit         uint32   `protobuf:"varint,8,opt,name=groupLimit,proto3" json:"groupLimit,omitempty"`
-	GroupScoreFuncName string   `protobuf:"bytes,9,opt,name=groupScoreFuncName,proto3" json:"groupScoreFuncName,omitempty"`
+	GroupScoreFuncName string   `protobuf:"bytes,9,opt,name=groupScoreFuncName,proto3" json:"groupScoreFuncName,omitempty"` // Max, Mean, Sum, CountWeighted or TopNMean, CountWeighted by default
 	Filters            []string `protobuf:"bytes,10,rep,name=filters,proto3" json:"filters,omitempty"`
 	ResultLimit        uint64   `protobuf:"varint,11,opt,name=resultLimit,proto3" json:"resultLimit,omitempty"`
 	GroupFilters       []string `protobuf:"bytes,12,rep,name=groupFilters,proto3" json:"groupFilters,omitempty"`
 	Uuid               string   `protobuf:"bytes,13,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	SearchK            int64    `protobuf:"varint,14,opt,name=searchK,proto3" json:"searchK,omitempty"` // ef for HNSW
+	Precision          float64  `protobuf:"fixed64,15,opt,name=precision,proto3" json:"precision,omitempty"`
+	RerankFactor       uint32   `protobuf:"varint,16,opt,name=rerankFactor,proto3" json:"rerankFactor,omitempty"`
+	Filter             string   `protobuf:"bytes,17,opt,name=filter,proto3" json:"filter,omitempty"`                    // e.g. label.category == "shoes" AND label.price < 100
+	CandidateBudget    uint64   `protobuf:"varint,18,opt,name=candidateBudget,proto3" json:"candidateBudget,omitempty"` // index candidates of a filtered search before an exact scan
+	ScoreExpression    string   `protobuf:"bytes,19,opt,name=scoreExpression,proto3" json:"scoreExpression,omitempty"`  // e.g. score - 0.1 * log1p(label.popularity)
+	MmrLambda          float64  `protobuf:"fixed64,20,opt,name=mmrLambda,proto3" json:"mmrLambda,omitempty"`            // between 0 and 1 re-ranks merged results with maximal marginal relevance
+	MaxPerGroup        uint32   `protobuf:"varint,21,opt,name=maxPerGroup,proto3" json:"maxPerGroup,omitempty"`         // maximum number of results with the same group label
+	RadiusSearch       bool     `protobuf:"varint,22,opt,name=radiusSearch,proto3" json:"radiusSearch,omitempty"`       // only datums with a vector score within radius are returned, up to limit
+	Radius             float64  `protobuf:"fixed64,23,opt,name=radius,proto3" json:"radius,omitempty"`
+	Offset             uint64   `protobuf:"varint,24,opt,name=offset,proto3" json:"offset,omitempty"`                 // number of merged results to skip for pagination
+	PageToken          string   `protobuf:"bytes,25,opt,name=pageToken,proto3" json:"pageToken,omitempty"`            // nextPageToken of the previous page
+	GroupScoreTopN     uint32   `protobuf:"varint,26,opt,name=groupScoreTopN,proto3" json:"groupScoreTopN,omitempty"` // number of best members averaged by the TopNMean group score function, default 3
+	Deterministic      bool     `protobuf:"varint,27,opt,name=deterministic,proto3" json:"deterministic,omitempty"`   // every source is queried and has to answer, score ties are broken by key
+	Quantized          bool     `protobuf:"varint,28,opt,name=quantized,proto3" json:"quantized,omitempty"`           // exact searches of quantized data score codes and re-rank the best candidates with full precision
 }
 
 func (x *SearchConfig) Reset() {
@@ -236,13 +251,122 @@ func (x *SearchConfig) GetUuid() string {
 	return ""
 }
 
+func (x *SearchConfig) GetSearchK() int64 {
+	if x != nil {
+		return x.SearchK
+	}
+	return 0
+}
+
+func (x *SearchConfig) GetPrecision() float64 {
+	if x != nil {
+		return x.Precision
+	}
+	return 0
+}
+
+func (x *SearchConfig) GetRerankFactor() uint32 {
+	if x != nil {
+		return x.RerankFactor
+	}
+	return 0
+}
+
+func (x *SearchConfig) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+func (x *SearchConfig) GetCandidateBudget() uint64 {
+	if x != nil {
+		return x.CandidateBudget
+	}
+	return 0
+}
+
+func (x *SearchConfig) GetScoreExpression() string {
+	if x != nil {
+		return x.ScoreExpression
+	}
+	return ""
+}
+
+func (x *SearchConfig) GetMmrLambda() float64 {
+	if x != nil {
+		return x.MmrLambda
+	}
+	return 0
+}
+
+func (x *SearchConfig) GetMaxPerGroup() uint32 {
+	if x != nil {
+		return x.MaxPerGroup
+	}
+	return 0
+}
+
+func (x *SearchConfig) GetRadiusSearch() bool {
+	if x != nil {
+		return x.RadiusSearch
+	}
+	return false
+}
+
+func (x *SearchConfig) GetRadius() float64 {
+	if x != nil {
+		return x.Radius
+	}
+	return 0
+}
+
+func (x *SearchConfig) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *SearchConfig) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *SearchConfig) GetGroupScoreTopN() uint32 {
+	if x != nil {
+		return x.GroupScoreTopN
+	}
+	return 0
+}
+
+func (x *SearchConfig) GetDeterministic() bool {
+	if x != nil {
+		return x.Deterministic
+	}
+	return false
+}
+
+func (x *SearchConfig) GetQuantized() bool {
+	if x != nil {
+		return x.Quantized
+	}
+	return false
+}
+
 type SearchContext struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Datum      []*Datum `protobuf:"bytes,1,rep,name=datum,proto3" json:"datum,omitempty"`
-	Prioritize bool     `protobuf:"varint,2,opt,name=prioritize,proto3" json:"prioritize,omitempty"`
+	Datum              []*Datum    `protobuf:"bytes,1,rep,name=datum,proto3" json:"datum,omitempty"`
+	Prioritize         bool        `protobuf:"varint,2,opt,name=prioritize,proto3" json:"prioritize,omitempty"`
+	NegativeDatum      []*Datum    `protobuf:"bytes,3,rep,name=negativeDatum,proto3" json:"negativeDatum,omitempty"`           // datums close to a negative datum are pushed down
+	NegativeWeight     float64     `protobuf:"fixed64,4,opt,name=negativeWeight,proto3" json:"negativeWeight,omitempty"`       // weight of the best score against negative datums, default 1
+	ExcludedKey        []*DatumKey `protobuf:"bytes,5,rep,name=excludedKey,proto3" json:"excludedKey,omitempty"`               // keys of datums that are not returned, e.g. already shown
+	ExcludedGroupLabel [][]byte    `protobuf:"bytes,6,rep,name=excludedGroupLabel,proto3" json:"excludedGroupLabel,omitempty"` // group labels of datums that are not returned
 }
 
 func (x *SearchContext) Reset() {
@@ -291,12 +415,41 @@ func (x *SearchContext) GetPrioritize() bool {
 	return false
 }
 
+func (x *SearchContext) GetNegativeDatum() []*Datum {
+	if x != nil {
+		return x.NegativeDatum
+	}
+	return nil
+}
+
+func (x *SearchContext) GetNegativeWeight() float64 {
+	if x != nil {
+		return x.NegativeWeight
+	}
+	return 0
+}
+
+func (x *SearchContext) GetExcludedKey() []*DatumKey {
+	if x != nil {
+		return x.ExcludedKey
+	}
+	return nil
+}
+
+func (x *SearchContext) GetExcludedGroupLabel() [][]byte {
+	if x != nil {
+		return x.ExcludedGroupLabel
+	}
+	return nil
+}
+
 type GetDataRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Name   string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Config *StreamConfig `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
 }
 
 func (x *GetDataRequest) Reset() {
@@ -338,6 +491,92 @@ func (x *GetDataRequest) GetName() string {
 	return ""
 }
 
+func (x *GetDataRequest) GetConfig() *StreamConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type StreamConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Fraction     float64  `protobuf:"fixed64,1,opt,name=fraction,proto3" json:"fraction,omitempty"`
+	Filters      []string `protobuf:"bytes,2,rep,name=filters,proto3" json:"filters,omitempty"`
+	GroupFilters []string `protobuf:"bytes,3,rep,name=groupFilters,proto3" json:"groupFilters,omitempty"`
+	Cluster      bool     `protobuf:"varint,4,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	Uuid         string   `protobuf:"bytes,5,opt,name=uuid,proto3" json:"uuid,omitempty"`
+}
+
+func (x *StreamConfig) Reset() {
+	*x = StreamConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamConfig) ProtoMessage() {}
+
+func (x *StreamConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamConfig.ProtoReflect.Descriptor instead.
+func (*StreamConfig) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StreamConfig) GetFraction() float64 {
+	if x != nil {
+		return x.Fraction
+	}
+	return 0
+}
+
+func (x *StreamConfig) GetFilters() []string {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *StreamConfig) GetGroupFilters() []string {
+	if x != nil {
+		return x.GroupFilters
+	}
+	return nil
+}
+
+func (x *StreamConfig) GetCluster() bool {
+	if x != nil {
+		return x.Cluster
+	}
+	return false
+}
+
+func (x *StreamConfig) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
 type Datum struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -350,7 +589,7 @@ type Datum struct {
 func (x *Datum) Reset() {
 	*x = Datum{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[4]
+		mi := &file_veriservice_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -363,7 +602,7 @@ func (x *Datum) String() string {
 func (*Datum) ProtoMessage() {}
 
 func (x *Datum) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[4]
+	mi := &file_veriservice_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -376,7 +615,7 @@ func (x *Datum) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Datum.ProtoReflect.Descriptor instead.
 func (*Datum) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{4}
+	return file_veriservice_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Datum) GetKey() *DatumKey {
@@ -409,7 +648,7 @@ type DatumKey struct {
 func (x *DatumKey) Reset() {
 	*x = DatumKey{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[5]
+		mi := &file_veriservice_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -422,7 +661,7 @@ func (x *DatumKey) String() string {
 func (*DatumKey) ProtoMessage() {}
 
 func (x *DatumKey) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[5]
+	mi := &file_veriservice_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -435,7 +674,7 @@ func (x *DatumKey) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DatumKey.ProtoReflect.Descriptor instead.
 func (*DatumKey) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{5}
+	return file_veriservice_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *DatumKey) GetFeature() []float32 {
@@ -492,7 +731,7 @@ type DatumValue struct {
 func (x *DatumValue) Reset() {
 	*x = DatumValue{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[6]
+		mi := &file_veriservice_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -505,7 +744,7 @@ func (x *DatumValue) String() string {
 func (*DatumValue) ProtoMessage() {}
 
 func (x *DatumValue) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[6]
+	mi := &file_veriservice_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -518,7 +757,7 @@ func (x *DatumValue) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DatumValue.ProtoReflect.Descriptor instead.
 func (*DatumValue) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{6}
+	return file_veriservice_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *DatumValue) GetVersion() uint64 {
@@ -540,14 +779,16 @@ type ScoredDatum struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Score float64 `protobuf:"fixed64,1,opt,name=score,proto3" json:"score,omitempty"`
-	Datum *Datum  `protobuf:"bytes,2,opt,name=datum,proto3" json:"datum,omitempty"`
+	Score       float64        `protobuf:"fixed64,1,opt,name=score,proto3" json:"score,omitempty"`
+	Datum       *Datum         `protobuf:"bytes,2,opt,name=datum,proto3" json:"datum,omitempty"`
+	VectorScore float64        `protobuf:"fixed64,3,opt,name=vectorScore,proto3" json:"vectorScore,omitempty"` // score before the score expression
+	Members     []*ScoredDatum `protobuf:"bytes,4,rep,name=members,proto3" json:"members,omitempty"`           // members of the group with the best first in a grouped search
 }
 
 func (x *ScoredDatum) Reset() {
 	*x = ScoredDatum{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[7]
+		mi := &file_veriservice_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -560,7 +801,7 @@ func (x *ScoredDatum) String() string {
 func (*ScoredDatum) ProtoMessage() {}
 
 func (x *ScoredDatum) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[7]
+	mi := &file_veriservice_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -573,7 +814,7 @@ func (x *ScoredDatum) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ScoredDatum.ProtoReflect.Descriptor instead.
 func (*ScoredDatum) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{7}
+	return file_veriservice_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ScoredDatum) GetScore() float64 {
@@ -590,6 +831,20 @@ func (x *ScoredDatum) GetDatum() *Datum {
 	return nil
 }
 
+func (x *ScoredDatum) GetVectorScore() float64 {
+	if x != nil {
+		return x.VectorScore
+	}
+	return 0
+}
+
+func (x *ScoredDatum) GetMembers() []*ScoredDatum {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
 type InsertDatumWithConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -602,7 +857,7 @@ type InsertDatumWithConfig struct {
 func (x *InsertDatumWithConfig) Reset() {
 	*x = InsertDatumWithConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[8]
+		mi := &file_veriservice_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -615,7 +870,7 @@ func (x *InsertDatumWithConfig) String() string {
 func (*InsertDatumWithConfig) ProtoMessage() {}
 
 func (x *InsertDatumWithConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[8]
+	mi := &file_veriservice_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -628,7 +883,7 @@ func (x *InsertDatumWithConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InsertDatumWithConfig.ProtoReflect.Descriptor instead.
 func (*InsertDatumWithConfig) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{8}
+	return file_veriservice_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *InsertDatumWithConfig) GetConfig() *InsertConfig {
@@ -650,13 +905,14 @@ type SearchResponse struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Result []*ScoredDatum `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
+	Result   []*ScoredDatum  `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
+	Metadata *SearchMetadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (x *SearchResponse) Reset() {
 	*x = SearchResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[9]
+		mi := &file_veriservice_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -669,7 +925,7 @@ func (x *SearchResponse) String() string {
 func (*SearchResponse) ProtoMessage() {}
 
 func (x *SearchResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[9]
+	mi := &file_veriservice_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -682,7 +938,7 @@ func (x *SearchResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
 func (*SearchResponse) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{9}
+	return file_veriservice_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *SearchResponse) GetResult() []*ScoredDatum {
@@ -692,33 +948,42 @@ func (x *SearchResponse) GetResult() []*ScoredDatum {
 	return nil
 }
 
-type InsertionRequest struct {
+func (x *SearchResponse) GetMetadata() *SearchMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type SearchMetadata struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Config   *InsertConfig `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
-	Datum    *Datum        `protobuf:"bytes,2,opt,name=datum,proto3" json:"datum,omitempty"`
-	DataName string        `protobuf:"bytes,3,opt,name=dataName,proto3" json:"dataName,omitempty"`
+	SourcesQueried  uint32 `protobuf:"varint,1,opt,name=sourcesQueried,proto3" json:"sourcesQueried,omitempty"`   // distinct sources queried for any query datum
+	SourcesAnswered uint32 `protobuf:"varint,2,opt,name=sourcesAnswered,proto3" json:"sourcesAnswered,omitempty"` // distinct sources that answered every query datum
+	TimedOut        bool   `protobuf:"varint,3,opt,name=timedOut,proto3" json:"timedOut,omitempty"`
+	NextPageToken   string `protobuf:"bytes,4,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"` // empty if the page is empty or diversified
+	Consistent      bool   `protobuf:"varint,5,opt,name=consistent,proto3" json:"consistent,omitempty"`      // every queried source answered before the timeout
 }
 
-func (x *InsertionRequest) Reset() {
-	*x = InsertionRequest{}
+func (x *SearchMetadata) Reset() {
+	*x = SearchMetadata{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[10]
+		mi := &file_veriservice_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *InsertionRequest) String() string {
+func (x *SearchMetadata) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InsertionRequest) ProtoMessage() {}
+func (*SearchMetadata) ProtoMessage() {}
 
-func (x *InsertionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[10]
+func (x *SearchMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -729,45 +994,122 @@ func (x *InsertionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InsertionRequest.ProtoReflect.Descriptor instead.
-func (*InsertionRequest) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use SearchMetadata.ProtoReflect.Descriptor instead.
+func (*SearchMetadata) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *InsertionRequest) GetConfig() *InsertConfig {
+func (x *SearchMetadata) GetSourcesQueried() uint32 {
 	if x != nil {
-		return x.Config
+		return x.SourcesQueried
 	}
-	return nil
+	return 0
 }
 
-func (x *InsertionRequest) GetDatum() *Datum {
+func (x *SearchMetadata) GetSourcesAnswered() uint32 {
 	if x != nil {
-		return x.Datum
+		return x.SourcesAnswered
 	}
-	return nil
+	return 0
 }
 
-func (x *InsertionRequest) GetDataName() string {
+func (x *SearchMetadata) GetTimedOut() bool {
 	if x != nil {
-		return x.DataName
+		return x.TimedOut
+	}
+	return false
+}
+
+func (x *SearchMetadata) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
 	}
 	return ""
 }
 
-type InsertConfig struct {
+func (x *SearchMetadata) GetConsistent() bool {
+	if x != nil {
+		return x.Consistent
+	}
+	return false
+}
+
+type InsertionRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	TTL   uint64 `protobuf:"varint,1,opt,name=tTL,proto3" json:"tTL,omitempty"`
-	Count uint64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Config   *InsertConfig `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	Datum    *Datum        `protobuf:"bytes,2,opt,name=datum,proto3" json:"datum,omitempty"`
+	DataName string        `protobuf:"bytes,3,opt,name=dataName,proto3" json:"dataName,omitempty"`
 }
 
-func (x *InsertConfig) Reset() {
+func (x *InsertionRequest) Reset() {
+	*x = InsertionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InsertionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InsertionRequest) ProtoMessage() {}
+
+func (x *InsertionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InsertionRequest.ProtoReflect.Descriptor instead.
+func (*InsertionRequest) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *InsertionRequest) GetConfig() *InsertConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *InsertionRequest) GetDatum() *Datum {
+	if x != nil {
+		return x.Datum
+	}
+	return nil
+}
+
+func (x *InsertionRequest) GetDataName() string {
+	if x != nil {
+		return x.DataName
+	}
+	return ""
+}
+
+type InsertConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TTL   uint64 `protobuf:"varint,1,opt,name=tTL,proto3" json:"tTL,omitempty"`
+	Count uint64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *InsertConfig) Reset() {
 	*x = InsertConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[11]
+		mi := &file_veriservice_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -780,7 +1122,7 @@ func (x *InsertConfig) String() string {
 func (*InsertConfig) ProtoMessage() {}
 
 func (x *InsertConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[11]
+	mi := &file_veriservice_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -793,7 +1135,7 @@ func (x *InsertConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InsertConfig.ProtoReflect.Descriptor instead.
 func (*InsertConfig) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{11}
+	return file_veriservice_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *InsertConfig) GetTTL() uint64 {
@@ -818,23 +1160,874 @@ type InsertionResponse struct {
 	Code int32 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
 }
 
-func (x *InsertionResponse) Reset() {
-	*x = InsertionResponse{}
+func (x *InsertionResponse) Reset() {
+	*x = InsertionResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InsertionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InsertionResponse) ProtoMessage() {}
+
+func (x *InsertionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InsertionResponse.ProtoReflect.Descriptor instead.
+func (*InsertionResponse) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *InsertionResponse) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+type InsertBatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DataName string                   `protobuf:"bytes,1,opt,name=dataName,proto3" json:"dataName,omitempty"`
+	Datum    []*InsertDatumWithConfig `protobuf:"bytes,2,rep,name=datum,proto3" json:"datum,omitempty"`
+}
+
+func (x *InsertBatchRequest) Reset() {
+	*x = InsertBatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InsertBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InsertBatchRequest) ProtoMessage() {}
+
+func (x *InsertBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InsertBatchRequest.ProtoReflect.Descriptor instead.
+func (*InsertBatchRequest) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *InsertBatchRequest) GetDataName() string {
+	if x != nil {
+		return x.DataName
+	}
+	return ""
+}
+
+func (x *InsertBatchRequest) GetDatum() []*InsertDatumWithConfig {
+	if x != nil {
+		return x.Datum
+	}
+	return nil
+}
+
+type InsertStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Applied bool   `protobuf:"varint,1,opt,name=applied,proto3" json:"applied,omitempty"` // false with an empty error if the datum is stale
+	Error   string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *InsertStatus) Reset() {
+	*x = InsertStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InsertStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InsertStatus) ProtoMessage() {}
+
+func (x *InsertStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InsertStatus.ProtoReflect.Descriptor instead.
+func (*InsertStatus) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *InsertStatus) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *InsertStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type InsertBatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code   int32           `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Status []*InsertStatus `protobuf:"bytes,2,rep,name=status,proto3" json:"status,omitempty"` // in the order of the inserted datums
+}
+
+func (x *InsertBatchResponse) Reset() {
+	*x = InsertBatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InsertBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InsertBatchResponse) ProtoMessage() {}
+
+func (x *InsertBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InsertBatchResponse.ProtoReflect.Descriptor instead.
+func (*InsertBatchResponse) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *InsertBatchResponse) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *InsertBatchResponse) GetStatus() []*InsertStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+type UpsertResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code    int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Applied bool   `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"` // false if the stored datum has a higher version or the key is deleted
+	Error   string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`      // set if the key is deleted, or if the write is applied but replicated less than replicationOnInsert
+}
+
+func (x *UpsertResponse) Reset() {
+	*x = UpsertResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpsertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertResponse) ProtoMessage() {}
+
+func (x *UpsertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertResponse.ProtoReflect.Descriptor instead.
+func (*UpsertResponse) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UpsertResponse) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *UpsertResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *UpsertResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DataName     string        `protobuf:"bytes,1,opt,name=dataName,proto3" json:"dataName,omitempty"`
+	Datum        []*Datum      `protobuf:"bytes,2,rep,name=datum,proto3" json:"datum,omitempty"`     // deleted by key, value is optional
+	Filters      []string      `protobuf:"bytes,3,rep,name=filters,proto3" json:"filters,omitempty"` // label filters as in StreamConfig, used when there is no datum
+	GroupFilters []string      `protobuf:"bytes,4,rep,name=groupFilters,proto3" json:"groupFilters,omitempty"`
+	Config       *DeleteConfig `protobuf:"bytes,5,opt,name=config,proto3" json:"config,omitempty"`
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteRequest) GetDataName() string {
+	if x != nil {
+		return x.DataName
+	}
+	return ""
+}
+
+func (x *DeleteRequest) GetDatum() []*Datum {
+	if x != nil {
+		return x.Datum
+	}
+	return nil
+}
+
+func (x *DeleteRequest) GetFilters() []string {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *DeleteRequest) GetGroupFilters() []string {
+	if x != nil {
+		return x.GroupFilters
+	}
+	return nil
+}
+
+func (x *DeleteRequest) GetConfig() *DeleteConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type DeleteConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TombstoneTTL uint64 `protobuf:"varint,1,opt,name=tombstoneTTL,proto3" json:"tombstoneTTL,omitempty"` // seconds to keep deleted keys, default is a day
+	Count        uint64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`               // number of hops, deletes by filters are forwarded for 3 hops
+	Uuid         string `protobuf:"bytes,3,opt,name=uuid,proto3" json:"uuid,omitempty"`                  // id of a delete by filters, a node applies it once
+}
+
+func (x *DeleteConfig) Reset() {
+	*x = DeleteConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteConfig) ProtoMessage() {}
+
+func (x *DeleteConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteConfig.ProtoReflect.Descriptor instead.
+func (*DeleteConfig) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DeleteConfig) GetTombstoneTTL() uint64 {
+	if x != nil {
+		return x.TombstoneTTL
+	}
+	return 0
+}
+
+func (x *DeleteConfig) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *DeleteConfig) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type GetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DataName string    `protobuf:"bytes,1,opt,name=dataName,proto3" json:"dataName,omitempty"`
+	Key      *DatumKey `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Local    bool      `protobuf:"varint,3,opt,name=local,proto3" json:"local,omitempty"` // peers are not asked on a miss
+}
+
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetRequest) GetDataName() string {
+	if x != nil {
+		return x.DataName
+	}
+	return ""
+}
+
+func (x *GetRequest) GetKey() *DatumKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *GetRequest) GetLocal() bool {
+	if x != nil {
+		return x.Local
+	}
+	return false
+}
+
+type GetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Found    bool   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Datum    *Datum `protobuf:"bytes,2,opt,name=datum,proto3" json:"datum,omitempty"`
+	ExpireAt int64  `protobuf:"varint,3,opt,name=expireAt,proto3" json:"expireAt,omitempty"` // unix seconds, 0 if the datum does not expire
+	TTL      uint64 `protobuf:"varint,4,opt,name=tTL,proto3" json:"tTL,omitempty"`           // remaining seconds
+}
+
+func (x *GetResponse) Reset() {
+	*x = GetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetResponse) ProtoMessage() {}
+
+func (x *GetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
+func (*GetResponse) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *GetResponse) GetDatum() *Datum {
+	if x != nil {
+		return x.Datum
+	}
+	return nil
+}
+
+func (x *GetResponse) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+func (x *GetResponse) GetTTL() uint64 {
+	if x != nil {
+		return x.TTL
+	}
+	return 0
+}
+
+type MultiGetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DataName string      `protobuf:"bytes,1,opt,name=dataName,proto3" json:"dataName,omitempty"`
+	Key      []*DatumKey `protobuf:"bytes,2,rep,name=key,proto3" json:"key,omitempty"`
+	Local    bool        `protobuf:"varint,3,opt,name=local,proto3" json:"local,omitempty"`
+}
+
+func (x *MultiGetRequest) Reset() {
+	*x = MultiGetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MultiGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiGetRequest) ProtoMessage() {}
+
+func (x *MultiGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiGetRequest.ProtoReflect.Descriptor instead.
+func (*MultiGetRequest) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *MultiGetRequest) GetDataName() string {
+	if x != nil {
+		return x.DataName
+	}
+	return ""
+}
+
+func (x *MultiGetRequest) GetKey() []*DatumKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *MultiGetRequest) GetLocal() bool {
+	if x != nil {
+		return x.Local
+	}
+	return false
+}
+
+type MultiGetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result []*GetResponse `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"` // in the order of keys
+}
+
+func (x *MultiGetResponse) Reset() {
+	*x = MultiGetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MultiGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiGetResponse) ProtoMessage() {}
+
+func (x *MultiGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiGetResponse.ProtoReflect.Descriptor instead.
+func (*MultiGetResponse) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *MultiGetResponse) GetResult() []*GetResponse {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type FieldQuery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DataName string `protobuf:"bytes,1,opt,name=dataName,proto3" json:"dataName,omitempty"`
+	Field    string `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`  // an indexed field of the data config
+	Value    string `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`  // JSON value, plain text is a string, empty counts datums per value
+	Limit    uint32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"` // number of datums to return, 0 only counts
+}
+
+func (x *FieldQuery) Reset() {
+	*x = FieldQuery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FieldQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldQuery) ProtoMessage() {}
+
+func (x *FieldQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldQuery.ProtoReflect.Descriptor instead.
+func (*FieldQuery) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *FieldQuery) GetDataName() string {
+	if x != nil {
+		return x.DataName
+	}
+	return ""
+}
+
+func (x *FieldQuery) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldQuery) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *FieldQuery) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type FieldValueCount struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Count uint64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *FieldValueCount) Reset() {
+	*x = FieldValueCount{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FieldValueCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldValueCount) ProtoMessage() {}
+
+func (x *FieldValueCount) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldValueCount.ProtoReflect.Descriptor instead.
+func (*FieldValueCount) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *FieldValueCount) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *FieldValueCount) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type FieldQueryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count  uint64             `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Datum  []*Datum           `protobuf:"bytes,2,rep,name=datum,proto3" json:"datum,omitempty"`
+	Values []*FieldValueCount `protobuf:"bytes,3,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *FieldQueryResponse) Reset() {
+	*x = FieldQueryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_veriservice_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FieldQueryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldQueryResponse) ProtoMessage() {}
+
+func (x *FieldQueryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldQueryResponse.ProtoReflect.Descriptor instead.
+func (*FieldQueryResponse) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *FieldQueryResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *FieldQueryResponse) GetDatum() []*Datum {
+	if x != nil {
+		return x.Datum
+	}
+	return nil
+}
+
+func (x *FieldQueryResponse) GetValues() []*FieldValueCount {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code    int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Deleted uint64 `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"` // number of datums deleted on the node
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[12]
+		mi := &file_veriservice_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *InsertionResponse) String() string {
+func (x *DeleteResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InsertionResponse) ProtoMessage() {}
+func (*DeleteResponse) ProtoMessage() {}
 
-func (x *InsertionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[12]
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_veriservice_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -845,18 +2038,25 @@ func (x *InsertionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InsertionResponse.ProtoReflect.Descriptor instead.
-func (*InsertionResponse) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_veriservice_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *InsertionResponse) GetCode() int32 {
+func (x *DeleteResponse) GetCode() int32 {
 	if x != nil {
 		return x.Code
 	}
 	return 0
 }
 
+func (x *DeleteResponse) GetDeleted() uint64 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
 type DataInfo struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -875,12 +2075,15 @@ type DataInfo struct {
 	ReplicationOnInsert        uint32    `protobuf:"varint,11,opt,name=replicationOnInsert,proto3" json:"replicationOnInsert,omitempty"`
 	EnforceReplicationOnInsert bool      `protobuf:"varint,12,opt,name=enforceReplicationOnInsert,proto3" json:"enforceReplicationOnInsert,omitempty"`
 	Retention                  uint64    `protobuf:"varint,13,opt,name=retention,proto3" json:"retention,omitempty"`
+	IndexBuildDuration         uint64    `protobuf:"varint,14,opt,name=indexBuildDuration,proto3" json:"indexBuildDuration,omitempty"` // milliseconds
+	IndexTrees                 int32     `protobuf:"varint,15,opt,name=indexTrees,proto3" json:"indexTrees,omitempty"`
+	IndexType                  string    `protobuf:"bytes,16,opt,name=indexType,proto3" json:"indexType,omitempty"`
 }
 
 func (x *DataInfo) Reset() {
 	*x = DataInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[13]
+		mi := &file_veriservice_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -893,7 +2096,7 @@ func (x *DataInfo) String() string {
 func (*DataInfo) ProtoMessage() {}
 
 func (x *DataInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[13]
+	mi := &file_veriservice_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -906,7 +2109,7 @@ func (x *DataInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DataInfo.ProtoReflect.Descriptor instead.
 func (*DataInfo) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{13}
+	return file_veriservice_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *DataInfo) GetName() string {
@@ -1000,25 +2203,56 @@ func (x *DataInfo) GetRetention() uint64 {
 	return 0
 }
 
+func (x *DataInfo) GetIndexBuildDuration() uint64 {
+	if x != nil {
+		return x.IndexBuildDuration
+	}
+	return 0
+}
+
+func (x *DataInfo) GetIndexTrees() int32 {
+	if x != nil {
+		return x.IndexTrees
+	}
+	return 0
+}
+
+func (x *DataInfo) GetIndexType() string {
+	if x != nil {
+		return x.IndexType
+	}
+	return ""
+}
+
 type DataConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name                       string  `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Version                    uint64  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
-	TargetN                    uint64  `protobuf:"varint,3,opt,name=targetN,proto3" json:"targetN,omitempty"`
-	TargetUtilization          float64 `protobuf:"fixed64,4,opt,name=targetUtilization,proto3" json:"targetUtilization,omitempty"`
-	NoTarget                   bool    `protobuf:"varint,5,opt,name=noTarget,proto3" json:"noTarget,omitempty"`
-	ReplicationOnInsert        uint32  `protobuf:"varint,6,opt,name=replicationOnInsert,proto3" json:"replicationOnInsert,omitempty"`
-	EnforceReplicationOnInsert bool    `protobuf:"varint,7,opt,name=enforceReplicationOnInsert,proto3" json:"enforceReplicationOnInsert,omitempty"`
-	Retention                  uint64  `protobuf:"varint,8,opt,name=retention,proto3" json:"retention,omitempty"`
+	Name                       string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version                    uint64   `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	TargetN                    uint64   `protobuf:"varint,3,opt,name=targetN,proto3" json:"targetN,omitempty"`
+	TargetUtilization          float64  `protobuf:"fixed64,4,opt,name=targetUtilization,proto3" json:"targetUtilization,omitempty"`
+	NoTarget                   bool     `protobuf:"varint,5,opt,name=noTarget,proto3" json:"noTarget,omitempty"`
+	ReplicationOnInsert        uint32   `protobuf:"varint,6,opt,name=replicationOnInsert,proto3" json:"replicationOnInsert,omitempty"`
+	EnforceReplicationOnInsert bool     `protobuf:"varint,7,opt,name=enforceReplicationOnInsert,proto3" json:"enforceReplicationOnInsert,omitempty"`
+	Retention                  uint64   `protobuf:"varint,8,opt,name=retention,proto3" json:"retention,omitempty"`
+	IndexMetrics               []string `protobuf:"bytes,9,rep,name=indexMetrics,proto3" json:"indexMetrics,omitempty"`
+	AnnoyTrees                 int32    `protobuf:"varint,10,opt,name=annoyTrees,proto3" json:"annoyTrees,omitempty"`
+	IndexType                  string   `protobuf:"bytes,11,opt,name=indexType,proto3" json:"indexType,omitempty"` // Annoy or HNSW
+	HnswM                      int32    `protobuf:"varint,12,opt,name=hnswM,proto3" json:"hnswM,omitempty"`
+	HnswEfConstruction         int32    `protobuf:"varint,13,opt,name=hnswEfConstruction,proto3" json:"hnswEfConstruction,omitempty"`
+	Quantization               string   `protobuf:"bytes,14,opt,name=quantization,proto3" json:"quantization,omitempty"` // PQ or SQ
+	PqSubspaces                uint32   `protobuf:"varint,15,opt,name=pqSubspaces,proto3" json:"pqSubspaces,omitempty"`
+	PqCentroids                uint32   `protobuf:"varint,16,opt,name=pqCentroids,proto3" json:"pqCentroids,omitempty"`
+	IndexedFields              []string `protobuf:"bytes,17,rep,name=indexedFields,proto3" json:"indexedFields,omitempty"` // e.g. label.category or group.brand
+	CodesOnly                  bool     `protobuf:"varint,18,opt,name=codesOnly,proto3" json:"codesOnly,omitempty"`        // quantized entries keep codes instead of features, datums are returned with decoded features
 }
 
 func (x *DataConfig) Reset() {
 	*x = DataConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[14]
+		mi := &file_veriservice_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1031,7 +2265,7 @@ func (x *DataConfig) String() string {
 func (*DataConfig) ProtoMessage() {}
 
 func (x *DataConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[14]
+	mi := &file_veriservice_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1044,7 +2278,7 @@ func (x *DataConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DataConfig.ProtoReflect.Descriptor instead.
 func (*DataConfig) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{14}
+	return file_veriservice_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *DataConfig) GetName() string {
@@ -1103,6 +2337,76 @@ func (x *DataConfig) GetRetention() uint64 {
 	return 0
 }
 
+func (x *DataConfig) GetIndexMetrics() []string {
+	if x != nil {
+		return x.IndexMetrics
+	}
+	return nil
+}
+
+func (x *DataConfig) GetAnnoyTrees() int32 {
+	if x != nil {
+		return x.AnnoyTrees
+	}
+	return 0
+}
+
+func (x *DataConfig) GetIndexType() string {
+	if x != nil {
+		return x.IndexType
+	}
+	return ""
+}
+
+func (x *DataConfig) GetHnswM() int32 {
+	if x != nil {
+		return x.HnswM
+	}
+	return 0
+}
+
+func (x *DataConfig) GetHnswEfConstruction() int32 {
+	if x != nil {
+		return x.HnswEfConstruction
+	}
+	return 0
+}
+
+func (x *DataConfig) GetQuantization() string {
+	if x != nil {
+		return x.Quantization
+	}
+	return ""
+}
+
+func (x *DataConfig) GetPqSubspaces() uint32 {
+	if x != nil {
+		return x.PqSubspaces
+	}
+	return 0
+}
+
+func (x *DataConfig) GetPqCentroids() uint32 {
+	if x != nil {
+		return x.PqCentroids
+	}
+	return 0
+}
+
+func (x *DataConfig) GetIndexedFields() []string {
+	if x != nil {
+		return x.IndexedFields
+	}
+	return nil
+}
+
+func (x *DataConfig) GetCodesOnly() bool {
+	if x != nil {
+		return x.CodesOnly
+	}
+	return false
+}
+
 type Peer struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1119,7 +2423,7 @@ type Peer struct {
 func (x *Peer) Reset() {
 	*x = Peer{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[15]
+		mi := &file_veriservice_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1132,7 +2436,7 @@ func (x *Peer) String() string {
 func (*Peer) ProtoMessage() {}
 
 func (x *Peer) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[15]
+	mi := &file_veriservice_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1145,7 +2449,7 @@ func (x *Peer) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Peer.ProtoReflect.Descriptor instead.
 func (*Peer) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{15}
+	return file_veriservice_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *Peer) GetAddressList() []string {
@@ -1201,7 +2505,7 @@ type JoinRequest struct {
 func (x *JoinRequest) Reset() {
 	*x = JoinRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[16]
+		mi := &file_veriservice_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1214,7 +2518,7 @@ func (x *JoinRequest) String() string {
 func (*JoinRequest) ProtoMessage() {}
 
 func (x *JoinRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[16]
+	mi := &file_veriservice_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1227,7 +2531,7 @@ func (x *JoinRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JoinRequest.ProtoReflect.Descriptor instead.
 func (*JoinRequest) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{16}
+	return file_veriservice_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *JoinRequest) GetPeer() *Peer {
@@ -1248,7 +2552,7 @@ type JoinResponse struct {
 func (x *JoinResponse) Reset() {
 	*x = JoinResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[17]
+		mi := &file_veriservice_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1261,7 +2565,7 @@ func (x *JoinResponse) String() string {
 func (*JoinResponse) ProtoMessage() {}
 
 func (x *JoinResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[17]
+	mi := &file_veriservice_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1274,7 +2578,7 @@ func (x *JoinResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JoinResponse.ProtoReflect.Descriptor instead.
 func (*JoinResponse) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{17}
+	return file_veriservice_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *JoinResponse) GetAddress() string {
@@ -1295,7 +2599,7 @@ type AddPeerRequest struct {
 func (x *AddPeerRequest) Reset() {
 	*x = AddPeerRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[18]
+		mi := &file_veriservice_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1308,7 +2612,7 @@ func (x *AddPeerRequest) String() string {
 func (*AddPeerRequest) ProtoMessage() {}
 
 func (x *AddPeerRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[18]
+	mi := &file_veriservice_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1321,7 +2625,7 @@ func (x *AddPeerRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddPeerRequest.ProtoReflect.Descriptor instead.
 func (*AddPeerRequest) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{18}
+	return file_veriservice_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *AddPeerRequest) GetPeer() *Peer {
@@ -1340,7 +2644,7 @@ type AddPeerResponse struct {
 func (x *AddPeerResponse) Reset() {
 	*x = AddPeerResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[19]
+		mi := &file_veriservice_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1353,7 +2657,7 @@ func (x *AddPeerResponse) String() string {
 func (*AddPeerResponse) ProtoMessage() {}
 
 func (x *AddPeerResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[19]
+	mi := &file_veriservice_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1366,7 +2670,7 @@ func (x *AddPeerResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddPeerResponse.ProtoReflect.Descriptor instead.
 func (*AddPeerResponse) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{19}
+	return file_veriservice_proto_rawDescGZIP(), []int{35}
 }
 
 type PingRequest struct {
@@ -1380,7 +2684,7 @@ type PingRequest struct {
 func (x *PingRequest) Reset() {
 	*x = PingRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[20]
+		mi := &file_veriservice_proto_msgTypes[36]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1393,7 +2697,7 @@ func (x *PingRequest) String() string {
 func (*PingRequest) ProtoMessage() {}
 
 func (x *PingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[20]
+	mi := &file_veriservice_proto_msgTypes[36]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1406,7 +2710,7 @@ func (x *PingRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
 func (*PingRequest) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{20}
+	return file_veriservice_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *PingRequest) GetTimestamp() uint64 {
@@ -1428,7 +2732,7 @@ type PingResponse struct {
 func (x *PingResponse) Reset() {
 	*x = PingResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_veriservice_proto_msgTypes[21]
+		mi := &file_veriservice_proto_msgTypes[37]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1441,7 +2745,7 @@ func (x *PingResponse) String() string {
 func (*PingResponse) ProtoMessage() {}
 
 func (x *PingResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_veriservice_proto_msgTypes[21]
+	mi := &file_veriservice_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1454,7 +2758,7 @@ func (x *PingResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PingResponse.ProtoReflect.Descriptor instead.
 func (*PingResponse) Descriptor() ([]byte, []int) {
-	return file_veriservice_proto_rawDescGZIP(), []int{21}
+	return file_veriservice_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *PingResponse) GetTimestamp() uint64 {
@@ -1486,7 +2790,7 @@ var file_veriservice_proto_rawDesc = []byte{
 	0x34, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
 	0x32, 0x1a, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53,
 	0x65, 0x61, 0x72, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x52, 0x07, 0x63, 0x6f,
-	0x6e, 0x74, 0x65, 0x78, 0x74, 0x22, 0xb0, 0x03, 0x0a, 0x0c, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68,
+	0x6e, 0x74, 0x65, 0x78, 0x74, 0x22, 0x96, 0x07, 0x0a, 0x0c, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68,
 	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x46,
 	0x75, 0x6e, 0x63, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73,
 	0x63, 0x6f, 0x72, 0x65, 0x46, 0x75, 0x6e, 0x63, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x0e,
@@ -1513,111 +2817,306 @@ var file_veriservice_proto_rawDesc = []byte{
 	0x12, 0x22, 0x0a, 0x0c, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73,
 	0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x46, 0x69, 0x6c,
 	0x74, 0x65, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x0d, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x22, 0x59, 0x0a, 0x0d, 0x53, 0x65, 0x61, 0x72,
-	0x63, 0x68, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x28, 0x0a, 0x05, 0x64, 0x61, 0x74,
-	0x75, 0x6d, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73,
+	0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x61, 0x72,
+	0x63, 0x68, 0x4b, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x65, 0x61, 0x72, 0x63,
+	0x68, 0x4b, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x70, 0x72, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x22, 0x0a, 0x0c, 0x72, 0x65, 0x72, 0x61, 0x6e, 0x6b, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x72, 0x65, 0x72, 0x61, 0x6e, 0x6b, 0x46, 0x61,
+	0x63, 0x74, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x11,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x28, 0x0a, 0x0f,
+	0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x18,
+	0x12, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x12, 0x28, 0x0a, 0x0f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x45,
+	0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x6d, 0x72, 0x4c, 0x61, 0x6d, 0x62, 0x64, 0x61, 0x18, 0x14, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x09, 0x6d, 0x6d, 0x72, 0x4c, 0x61, 0x6d, 0x62, 0x64, 0x61, 0x12, 0x20,
+	0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x50, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x15, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x50, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x12, 0x22, 0x0a, 0x0c, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68,
+	0x18, 0x16, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x53, 0x65,
+	0x61, 0x72, 0x63, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x18, 0x17,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x18, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x66,
+	0x66, 0x73, 0x65, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x19, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x12, 0x26, 0x0a, 0x0e, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x53, 0x63, 0x6f, 0x72, 0x65,
+	0x54, 0x6f, 0x70, 0x4e, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x54, 0x6f, 0x70, 0x4e, 0x12, 0x24, 0x0a, 0x0d, 0x64, 0x65,
+	0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74, 0x69, 0x63, 0x18, 0x1b, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0d, 0x64, 0x65, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74, 0x69, 0x63,
+	0x12, 0x1c, 0x0a, 0x09, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x7a, 0x65, 0x64, 0x18, 0x1c, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x09, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x7a, 0x65, 0x64, 0x22, 0xa4,
+	0x02, 0x0a, 0x0d, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74,
+	0x12, 0x28, 0x0a, 0x05, 0x64, 0x61, 0x74, 0x75, 0x6d, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x61,
+	0x74, 0x75, 0x6d, 0x52, 0x05, 0x64, 0x61, 0x74, 0x75, 0x6d, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72,
+	0x69, 0x6f, 0x72, 0x69, 0x74, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x69, 0x7a, 0x65, 0x12, 0x38, 0x0a, 0x0d, 0x6e, 0x65,
+	0x67, 0x61, 0x74, 0x69, 0x76, 0x65, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x44, 0x61, 0x74, 0x75, 0x6d, 0x52, 0x0d, 0x6e, 0x65, 0x67, 0x61, 0x74, 0x69, 0x76, 0x65, 0x44,
+	0x61, 0x74, 0x75, 0x6d, 0x12, 0x26, 0x0a, 0x0e, 0x6e, 0x65, 0x67, 0x61, 0x74, 0x69, 0x76, 0x65,
+	0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x6e, 0x65,
+	0x67, 0x61, 0x74, 0x69, 0x76, 0x65, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x37, 0x0a, 0x0b,
+	0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x44, 0x61, 0x74, 0x75, 0x6d, 0x4b, 0x65, 0x79, 0x52, 0x0b, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x64, 0x4b, 0x65, 0x79, 0x12, 0x2e, 0x0a, 0x12, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65,
+	0x64, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x0c, 0x52, 0x12, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0x57, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x31, 0x0a, 0x06, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x65,
+	0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x96,
+	0x01, 0x0a, 0x0c, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x1a, 0x0a, 0x08, 0x66, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x08, 0x66, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x66,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x66, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x46, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x63, 0x6c, 0x75, 0x73,
+	0x74, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x22, 0x5f, 0x0a, 0x05, 0x44, 0x61, 0x74, 0x75, 0x6d,
+	0x12, 0x27, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x75,
+	0x6d, 0x4b, 0x65, 0x79, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2d, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x98, 0x01, 0x0a, 0x08, 0x44, 0x61, 0x74,
+	0x75, 0x6d, 0x4b, 0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x02, 0x52, 0x07, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12,
+	0x1e, 0x0a, 0x0a, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0a, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12,
+	0x14, 0x0a, 0x05, 0x73, 0x69, 0x7a, 0x65, 0x31, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05,
+	0x73, 0x69, 0x7a, 0x65, 0x31, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x7a, 0x65, 0x32, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x73, 0x69, 0x7a, 0x65, 0x32, 0x12, 0x12, 0x0a, 0x04, 0x64,
+	0x69, 0x6d, 0x31, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x64, 0x69, 0x6d, 0x31, 0x12,
+	0x12, 0x0a, 0x04, 0x64, 0x69, 0x6d, 0x32, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x64,
+	0x69, 0x6d, 0x32, 0x22, 0x3c, 0x0a, 0x0a, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x22, 0xa3, 0x01, 0x0a, 0x0b, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x64, 0x44, 0x61, 0x74, 0x75,
+	0x6d, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x64, 0x61, 0x74, 0x75, 0x6d,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x52, 0x05, 0x64, 0x61, 0x74, 0x75,
+	0x6d, 0x12, 0x20, 0x0a, 0x0b, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x53, 0x63, 0x6f, 0x72, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x53, 0x63,
+	0x6f, 0x72, 0x65, 0x12, 0x32, 0x0a, 0x07, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x64, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x52, 0x07,
+	0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x22, 0x74, 0x0a, 0x15, 0x49, 0x6e, 0x73, 0x65, 0x72,
+	0x74, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x57, 0x69, 0x74, 0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x12, 0x31, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x49,
+	0x6e, 0x73, 0x65, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x12, 0x28, 0x0a, 0x05, 0x64, 0x61, 0x74, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x52, 0x05, 0x64, 0x61, 0x74, 0x75, 0x6d, 0x22, 0x7b, 0x0a,
+	0x0e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x30, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x63,
+	0x6f, 0x72, 0x65, 0x64, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x37, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0xc4, 0x01, 0x0a, 0x0e, 0x53,
+	0x65, 0x61, 0x72, 0x63, 0x68, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x26, 0x0a,
+	0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x51, 0x75,
+	0x65, 0x72, 0x69, 0x65, 0x64, 0x12, 0x28, 0x0a, 0x0f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73,
+	0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x65, 0x64, 0x12,
+	0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x64, 0x4f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x64, 0x4f, 0x75, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x6e,
+	0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e,
+	0x74, 0x22, 0x8b, 0x01, 0x0a, 0x10, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x28, 0x0a, 0x05, 0x64, 0x61, 0x74,
+	0x75, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73,
 	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x52, 0x05, 0x64, 0x61,
-	0x74, 0x75, 0x6d, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x69, 0x7a,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74,
-	0x69, 0x7a, 0x65, 0x22, 0x24, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x5f, 0x0a, 0x05, 0x44, 0x61, 0x74,
-	0x75, 0x6d, 0x12, 0x27, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x15, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x61,
-	0x74, 0x75, 0x6d, 0x4b, 0x65, 0x79, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2d, 0x0a, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x76, 0x65, 0x72,
-	0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x56, 0x61,
-	0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x98, 0x01, 0x0a, 0x08, 0x44,
-	0x61, 0x74, 0x75, 0x6d, 0x4b, 0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x66, 0x65, 0x61, 0x74, 0x75,
-	0x72, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x02, 0x52, 0x07, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72,
-	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x4c, 0x61, 0x62, 0x65,
-	0x6c, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x7a, 0x65, 0x31, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x05, 0x73, 0x69, 0x7a, 0x65, 0x31, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x7a, 0x65, 0x32,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x73, 0x69, 0x7a, 0x65, 0x32, 0x12, 0x12, 0x0a,
-	0x04, 0x64, 0x69, 0x6d, 0x31, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x64, 0x69, 0x6d,
-	0x31, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x69, 0x6d, 0x32, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x04, 0x64, 0x69, 0x6d, 0x32, 0x22, 0x3c, 0x0a, 0x0a, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x56, 0x61,
-	0x6c, 0x75, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a,
-	0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6c, 0x61,
-	0x62, 0x65, 0x6c, 0x22, 0x4d, 0x0a, 0x0b, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x64, 0x44, 0x61, 0x74,
-	0x75, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x01, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x64, 0x61, 0x74, 0x75,
-	0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x52, 0x05, 0x64, 0x61, 0x74,
-	0x75, 0x6d, 0x22, 0x74, 0x0a, 0x15, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x44, 0x61, 0x74, 0x75,
-	0x6d, 0x57, 0x69, 0x74, 0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x31, 0x0a, 0x06, 0x63,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x65,
-	0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x28,
-	0x0a, 0x05, 0x64, 0x61, 0x74, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x74, 0x75, 0x6d, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x4e, 0x61, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x4e, 0x61, 0x6d, 0x65, 0x22,
+	0x36, 0x0a, 0x0c, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x10, 0x0a, 0x03, 0x74, 0x54, 0x4c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x74, 0x54,
+	0x4c, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x27, 0x0a, 0x11, 0x49, 0x6e, 0x73, 0x65, 0x72,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x22, 0x6a, 0x0a, 0x12, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x4e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x38, 0x0a, 0x05, 0x64, 0x61, 0x74, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x22, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x57, 0x69, 0x74, 0x68, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x05, 0x64, 0x61, 0x74, 0x75, 0x6d, 0x22, 0x3e, 0x0a, 0x0c,
+	0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x61,
+	0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x5c, 0x0a, 0x13,
+	0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x31, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x54, 0x0a, 0x0e, 0x55, 0x70,
+	0x73, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x22, 0xc6, 0x01, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x28,
+	0x0a, 0x05, 0x64, 0x61, 0x74, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e,
 	0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x75,
-	0x6d, 0x52, 0x05, 0x64, 0x61, 0x74, 0x75, 0x6d, 0x22, 0x42, 0x0a, 0x0e, 0x53, 0x65, 0x61, 0x72,
-	0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x06, 0x72, 0x65,
-	0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x65, 0x72,
-	0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x64, 0x44,
-	0x61, 0x74, 0x75, 0x6d, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x8b, 0x01, 0x0a,
-	0x10, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x31, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x19, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
-	0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x12, 0x28, 0x0a, 0x05, 0x64, 0x61, 0x74, 0x75, 0x6d, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x2e, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x52, 0x05, 0x64, 0x61, 0x74, 0x75, 0x6d, 0x12, 0x1a,
-	0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x36, 0x0a, 0x0c, 0x49, 0x6e,
-	0x73, 0x65, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x54,
-	0x4c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x74, 0x54, 0x4c, 0x12, 0x14, 0x0a, 0x05,
-	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75,
-	0x6e, 0x74, 0x22, 0x27, 0x0a, 0x11, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x22, 0xa0, 0x03, 0x0a, 0x08,
-	0x44, 0x61, 0x74, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09,
-	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72,
-	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x76, 0x67, 0x18, 0x04, 0x20, 0x03, 0x28,
-	0x02, 0x52, 0x03, 0x61, 0x76, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x69, 0x73, 0x74, 0x18, 0x05,
-	0x20, 0x03, 0x28, 0x02, 0x52, 0x04, 0x68, 0x69, 0x73, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x6e, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x01, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x44,
-	0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x6d,
-	0x61, 0x78, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x61,
-	0x72, 0x67, 0x65, 0x74, 0x4e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x74, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x4e, 0x12, 0x2c, 0x0a, 0x11, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x55, 0x74,
-	0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52,
-	0x11, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6e, 0x6f, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x0a,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6e, 0x6f, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x30,
-	0x0a, 0x13, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x6e, 0x49,
-	0x6e, 0x73, 0x65, 0x72, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x13, 0x72, 0x65, 0x70,
-	0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x6e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74,
-	0x12, 0x3e, 0x0a, 0x1a, 0x65, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x69,
-	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x6e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x18, 0x0c,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x1a, 0x65, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x52, 0x65, 0x70,
-	0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x6e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74,
-	0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0d, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xae,
-	0x02, 0x0a, 0x0a, 0x44, 0x61, 0x74, 0x61, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x12, 0x0a,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x74,
-	0x61, 0x72, 0x67, 0x65, 0x74, 0x4e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x74, 0x61,
-	0x72, 0x67, 0x65, 0x74, 0x4e, 0x12, 0x2c, 0x0a, 0x11, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x55,
-	0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01,
-	0x52, 0x11, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6e, 0x6f, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6e, 0x6f, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12,
-	0x30, 0x0a, 0x13, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x6e,
-	0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x13, 0x72, 0x65,
-	0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x6e, 0x49, 0x6e, 0x73, 0x65, 0x72,
-	0x74, 0x12, 0x3e, 0x0a, 0x1a, 0x65, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c,
-	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x6e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x18,
-	0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x1a, 0x65, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x52, 0x65,
-	0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x6e, 0x49, 0x6e, 0x73, 0x65, 0x72,
-	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x22,
+	0x6d, 0x52, 0x05, 0x64, 0x61, 0x74, 0x75, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x66, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x66, 0x69, 0x6c, 0x74, 0x65,
+	0x72, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x46, 0x69, 0x6c, 0x74, 0x65,
+	0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x46,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x12, 0x31, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x5c, 0x0a, 0x0c, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x22, 0x0a, 0x0c, 0x74, 0x6f, 0x6d,
+	0x62, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x54, 0x54, 0x4c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0c, 0x74, 0x6f, 0x6d, 0x62, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x54, 0x54, 0x4c, 0x12, 0x14, 0x0a,
+	0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x22, 0x67, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x4e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x27, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x61, 0x74,
+	0x75, 0x6d, 0x4b, 0x65, 0x79, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f,
+	0x63, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
+	0x22, 0x7b, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05,
+	0x66, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x28, 0x0a, 0x05, 0x64, 0x61, 0x74, 0x75, 0x6d, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x52, 0x05, 0x64, 0x61, 0x74, 0x75, 0x6d, 0x12,
+	0x1a, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x41, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x08, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x41, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x74,
+	0x54, 0x4c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x74, 0x54, 0x4c, 0x22, 0x6c, 0x0a,
+	0x0f, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1a, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x27, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x76, 0x65, 0x72, 0x69,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x4b, 0x65, 0x79,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x22, 0x44, 0x0a, 0x10, 0x4d,
+	0x75, 0x6c, 0x74, 0x69, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x30, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x22, 0x6a, 0x0a, 0x0a, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12,
+	0x1a, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x3d, 0x0a,
+	0x0f, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x8a, 0x01, 0x0a,
+	0x12, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x05, 0x64, 0x61, 0x74,
+	0x75, 0x6d, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x75, 0x6d, 0x52, 0x05, 0x64, 0x61,
+	0x74, 0x75, 0x6d, 0x12, 0x34, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x3e, 0x0a, 0x0e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63,
+	0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x22, 0x8e, 0x04, 0x0a, 0x08, 0x44, 0x61,
+	0x74, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x76, 0x67, 0x18, 0x04, 0x20, 0x03, 0x28, 0x02, 0x52,
+	0x03, 0x61, 0x76, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x69, 0x73, 0x74, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x02, 0x52, 0x04, 0x68, 0x69, 0x73, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x6e, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x01, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x44, 0x69, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x6d, 0x61, 0x78,
+	0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x4e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x4e, 0x12, 0x2c, 0x0a, 0x11, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x55, 0x74, 0x69, 0x6c,
+	0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x1a, 0x0a, 0x08, 0x6e, 0x6f, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x6e, 0x6f, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x30, 0x0a, 0x13,
+	0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x6e, 0x49, 0x6e, 0x73,
+	0x65, 0x72, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x13, 0x72, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x6e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x12, 0x3e,
+	0x0a, 0x1a, 0x65, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x6e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x1a, 0x65, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x6e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x12,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x54, 0x72, 0x65, 0x65, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0a, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x54, 0x72, 0x65, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x54, 0x79, 0x70, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x54, 0x79, 0x70, 0x65, 0x22, 0x82, 0x05, 0x0a, 0x0a, 0x44,
+	0x61, 0x74, 0x61, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x4e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x4e, 0x12, 0x2c, 0x0a, 0x11, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x55, 0x74, 0x69, 0x6c, 0x69,
+	0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1a, 0x0a, 0x08, 0x6e, 0x6f, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x08, 0x6e, 0x6f, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x30, 0x0a, 0x13, 0x72,
+	0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x6e, 0x49, 0x6e, 0x73, 0x65,
+	0x72, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x13, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x6e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x12, 0x3e, 0x0a,
+	0x1a, 0x65, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x4f, 0x6e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x1a, 0x65, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x6e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x12, 0x1c, 0x0a,
+	0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0c, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12,
+	0x1e, 0x0a, 0x0a, 0x61, 0x6e, 0x6e, 0x6f, 0x79, 0x54, 0x72, 0x65, 0x65, 0x73, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0a, 0x61, 0x6e, 0x6e, 0x6f, 0x79, 0x54, 0x72, 0x65, 0x65, 0x73, 0x12,
+	0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x54, 0x79, 0x70, 0x65, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x68, 0x6e, 0x73, 0x77, 0x4d, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x68, 0x6e,
+	0x73, 0x77, 0x4d, 0x12, 0x2e, 0x0a, 0x12, 0x68, 0x6e, 0x73, 0x77, 0x45, 0x66, 0x43, 0x6f, 0x6e,
+	0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x12, 0x68, 0x6e, 0x73, 0x77, 0x45, 0x66, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x7a, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x71, 0x75, 0x61, 0x6e, 0x74,
+	0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x71, 0x53, 0x75, 0x62,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x70, 0x71,
+	0x53, 0x75, 0x62, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x71, 0x43,
+	0x65, 0x6e, 0x74, 0x72, 0x6f, 0x69, 0x64, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b,
+	0x70, 0x71, 0x43, 0x65, 0x6e, 0x74, 0x72, 0x6f, 0x69, 0x64, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x65, 0x64, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x11, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0d, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x64, 0x46, 0x69, 0x65, 0x6c, 0x64,
+	0x73, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x64, 0x65, 0x73, 0x4f, 0x6e, 0x6c, 0x79, 0x18, 0x12,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x64, 0x65, 0x73, 0x4f, 0x6e, 0x6c, 0x79, 0x22,
 	0xcb, 0x01, 0x0a, 0x04, 0x50, 0x65, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x64, 0x64, 0x72,
 	0x65, 0x73, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x61,
 	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
@@ -1649,7 +3148,7 @@ var file_veriservice_proto_rawDesc = []byte{
 	0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
 	0x74, 0x61, 0x6d, 0x70, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
 	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x32, 0x80, 0x05, 0x0a, 0x0b, 0x56, 0x65, 0x72, 0x69, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x32, 0x88, 0x09, 0x0a, 0x0b, 0x56, 0x65, 0x72, 0x69, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
 	0x12, 0x43, 0x0a, 0x06, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x12, 0x1a, 0x2e, 0x76, 0x65, 0x72,
 	0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52,
 	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72,
@@ -1689,7 +3188,40 @@ var file_veriservice_proto_rawDesc = []byte{
 	0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52,
 	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72,
 	0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x06, 0x55, 0x70, 0x73, 0x65, 0x72, 0x74, 0x12, 0x1d, 0x2e,
+	0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x49, 0x6e, 0x73, 0x65,
+	0x72, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x76,
+	0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x55, 0x70, 0x73, 0x65, 0x72,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x06, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x1a, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1b, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x52, 0x0a, 0x0b, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12,
+	0x1f, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x49, 0x6e,
+	0x73, 0x65, 0x72, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x20, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x49,
+	0x6e, 0x73, 0x65, 0x72, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x53, 0x0a, 0x0c, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x12, 0x1d, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x3a, 0x0a, 0x03, 0x47, 0x65, 0x74,
+	0x12, 0x17, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47,
+	0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x76, 0x65, 0x72, 0x69,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x08, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x47, 0x65,
+	0x74, 0x12, 0x1c, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x4d, 0x75, 0x6c, 0x74, 0x69, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1d, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4d, 0x75,
+	0x6c, 0x74, 0x69, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x49, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x79, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12,
+	0x17, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x51, 0x75, 0x65, 0x72, 0x79, 0x1a, 0x1f, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1704,70 +3236,115 @@ func file_veriservice_proto_rawDescGZIP() []byte {
 	return file_veriservice_proto_rawDescData
 }
 
-var file_veriservice_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_veriservice_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
 var file_veriservice_proto_goTypes = []interface{}{
 	(*SearchRequest)(nil),         // 0: veriservice.SearchRequest
 	(*SearchConfig)(nil),          // 1: veriservice.SearchConfig
 	(*SearchContext)(nil),         // 2: veriservice.SearchContext
 	(*GetDataRequest)(nil),        // 3: veriservice.GetDataRequest
-	(*Datum)(nil),                 // 4: veriservice.Datum
-	(*DatumKey)(nil),              // 5: veriservice.DatumKey
-	(*DatumValue)(nil),            // 6: veriservice.DatumValue
-	(*ScoredDatum)(nil),           // 7: veriservice.ScoredDatum
-	(*InsertDatumWithConfig)(nil), // 8: veriservice.InsertDatumWithConfig
-	(*SearchResponse)(nil),        // 9: veriservice.SearchResponse
-	(*InsertionRequest)(nil),      // 10: veriservice.InsertionRequest
-	(*InsertConfig)(nil),          // 11: veriservice.InsertConfig
-	(*InsertionResponse)(nil),     // 12: veriservice.InsertionResponse
-	(*DataInfo)(nil),              // 13: veriservice.DataInfo
-	(*DataConfig)(nil),            // 14: veriservice.DataConfig
-	(*Peer)(nil),                  // 15: veriservice.Peer
-	(*JoinRequest)(nil),           // 16: veriservice.JoinRequest
-	(*JoinResponse)(nil),          // 17: veriservice.JoinResponse
-	(*AddPeerRequest)(nil),        // 18: veriservice.AddPeerRequest
-	(*AddPeerResponse)(nil),       // 19: veriservice.AddPeerResponse
-	(*PingRequest)(nil),           // 20: veriservice.PingRequest
-	(*PingResponse)(nil),          // 21: veriservice.PingResponse
+	(*StreamConfig)(nil),          // 4: veriservice.StreamConfig
+	(*Datum)(nil),                 // 5: veriservice.Datum
+	(*DatumKey)(nil),              // 6: veriservice.DatumKey
+	(*DatumValue)(nil),            // 7: veriservice.DatumValue
+	(*ScoredDatum)(nil),           // 8: veriservice.ScoredDatum
+	(*InsertDatumWithConfig)(nil), // 9: veriservice.InsertDatumWithConfig
+	(*SearchResponse)(nil),        // 10: veriservice.SearchResponse
+	(*SearchMetadata)(nil),        // 11: veriservice.SearchMetadata
+	(*InsertionRequest)(nil),      // 12: veriservice.InsertionRequest
+	(*InsertConfig)(nil),          // 13: veriservice.InsertConfig
+	(*InsertionResponse)(nil),     // 14: veriservice.InsertionResponse
+	(*InsertBatchRequest)(nil),    // 15: veriservice.InsertBatchRequest
+	(*InsertStatus)(nil),          // 16: veriservice.InsertStatus
+	(*InsertBatchResponse)(nil),   // 17: veriservice.InsertBatchResponse
+	(*UpsertResponse)(nil),        // 18: veriservice.UpsertResponse
+	(*DeleteRequest)(nil),         // 19: veriservice.DeleteRequest
+	(*DeleteConfig)(nil),          // 20: veriservice.DeleteConfig
+	(*GetRequest)(nil),            // 21: veriservice.GetRequest
+	(*GetResponse)(nil),           // 22: veriservice.GetResponse
+	(*MultiGetRequest)(nil),       // 23: veriservice.MultiGetRequest
+	(*MultiGetResponse)(nil),      // 24: veriservice.MultiGetResponse
+	(*FieldQuery)(nil),            // 25: veriservice.FieldQuery
+	(*FieldValueCount)(nil),       // 26: veriservice.FieldValueCount
+	(*FieldQueryResponse)(nil),    // 27: veriservice.FieldQueryResponse
+	(*DeleteResponse)(nil),        // 28: veriservice.DeleteResponse
+	(*DataInfo)(nil),              // 29: veriservice.DataInfo
+	(*DataConfig)(nil),            // 30: veriservice.DataConfig
+	(*Peer)(nil),                  // 31: veriservice.Peer
+	(*JoinRequest)(nil),           // 32: veriservice.JoinRequest
+	(*JoinResponse)(nil),          // 33: veriservice.JoinResponse
+	(*AddPeerRequest)(nil),        // 34: veriservice.AddPeerRequest
+	(*AddPeerResponse)(nil),       // 35: veriservice.AddPeerResponse
+	(*PingRequest)(nil),           // 36: veriservice.PingRequest
+	(*PingResponse)(nil),          // 37: veriservice.PingResponse
 }
 var file_veriservice_proto_depIdxs = []int32{
 	1,  // 0: veriservice.SearchRequest.config:type_name -> veriservice.SearchConfig
-	4,  // 1: veriservice.SearchRequest.datum:type_name -> veriservice.Datum
+	5,  // 1: veriservice.SearchRequest.datum:type_name -> veriservice.Datum
 	2,  // 2: veriservice.SearchRequest.context:type_name -> veriservice.SearchContext
-	4,  // 3: veriservice.SearchContext.datum:type_name -> veriservice.Datum
-	5,  // 4: veriservice.Datum.key:type_name -> veriservice.DatumKey
-	6,  // 5: veriservice.Datum.value:type_name -> veriservice.DatumValue
-	4,  // 6: veriservice.ScoredDatum.datum:type_name -> veriservice.Datum
-	11, // 7: veriservice.InsertDatumWithConfig.config:type_name -> veriservice.InsertConfig
-	4,  // 8: veriservice.InsertDatumWithConfig.datum:type_name -> veriservice.Datum
-	7,  // 9: veriservice.SearchResponse.result:type_name -> veriservice.ScoredDatum
-	11, // 10: veriservice.InsertionRequest.config:type_name -> veriservice.InsertConfig
-	4,  // 11: veriservice.InsertionRequest.datum:type_name -> veriservice.Datum
-	14, // 12: veriservice.Peer.dataList:type_name -> veriservice.DataConfig
-	15, // 13: veriservice.JoinRequest.peer:type_name -> veriservice.Peer
-	15, // 14: veriservice.AddPeerRequest.peer:type_name -> veriservice.Peer
-	0,  // 15: veriservice.VeriService.Search:input_type -> veriservice.SearchRequest
-	10, // 16: veriservice.VeriService.Insert:input_type -> veriservice.InsertionRequest
-	16, // 17: veriservice.VeriService.Join:input_type -> veriservice.JoinRequest
-	18, // 18: veriservice.VeriService.AddPeer:input_type -> veriservice.AddPeerRequest
-	3,  // 19: veriservice.VeriService.DataStream:input_type -> veriservice.GetDataRequest
-	14, // 20: veriservice.VeriService.CreateDataIfNotExists:input_type -> veriservice.DataConfig
-	3,  // 21: veriservice.VeriService.GetDataInfo:input_type -> veriservice.GetDataRequest
-	0,  // 22: veriservice.VeriService.SearchStream:input_type -> veriservice.SearchRequest
-	20, // 23: veriservice.VeriService.Ping:input_type -> veriservice.PingRequest
-	9,  // 24: veriservice.VeriService.Search:output_type -> veriservice.SearchResponse
-	12, // 25: veriservice.VeriService.Insert:output_type -> veriservice.InsertionResponse
-	17, // 26: veriservice.VeriService.Join:output_type -> veriservice.JoinResponse
-	19, // 27: veriservice.VeriService.AddPeer:output_type -> veriservice.AddPeerResponse
-	4,  // 28: veriservice.VeriService.DataStream:output_type -> veriservice.Datum
-	13, // 29: veriservice.VeriService.CreateDataIfNotExists:output_type -> veriservice.DataInfo
-	13, // 30: veriservice.VeriService.GetDataInfo:output_type -> veriservice.DataInfo
-	7,  // 31: veriservice.VeriService.SearchStream:output_type -> veriservice.ScoredDatum
-	21, // 32: veriservice.VeriService.Ping:output_type -> veriservice.PingResponse
-	24, // [24:33] is the sub-list for method output_type
-	15, // [15:24] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	5,  // 3: veriservice.SearchContext.datum:type_name -> veriservice.Datum
+	5,  // 4: veriservice.SearchContext.negativeDatum:type_name -> veriservice.Datum
+	6,  // 5: veriservice.SearchContext.excludedKey:type_name -> veriservice.DatumKey
+	4,  // 6: veriservice.GetDataRequest.config:type_name -> veriservice.StreamConfig
+	6,  // 7: veriservice.Datum.key:type_name -> veriservice.DatumKey
+	7,  // 8: veriservice.Datum.value:type_name -> veriservice.DatumValue
+	5,  // 9: veriservice.ScoredDatum.datum:type_name -> veriservice.Datum
+	8,  // 10: veriservice.ScoredDatum.members:type_name -> veriservice.ScoredDatum
+	13, // 11: veriservice.InsertDatumWithConfig.config:type_name -> veriservice.InsertConfig
+	5,  // 12: veriservice.InsertDatumWithConfig.datum:type_name -> veriservice.Datum
+	8,  // 13: veriservice.SearchResponse.result:type_name -> veriservice.ScoredDatum
+	11, // 14: veriservice.SearchResponse.metadata:type_name -> veriservice.SearchMetadata
+	13, // 15: veriservice.InsertionRequest.config:type_name -> veriservice.InsertConfig
+	5,  // 16: veriservice.InsertionRequest.datum:type_name -> veriservice.Datum
+	9,  // 17: veriservice.InsertBatchRequest.datum:type_name -> veriservice.InsertDatumWithConfig
+	16, // 18: veriservice.InsertBatchResponse.status:type_name -> veriservice.InsertStatus
+	5,  // 19: veriservice.DeleteRequest.datum:type_name -> veriservice.Datum
+	20, // 20: veriservice.DeleteRequest.config:type_name -> veriservice.DeleteConfig
+	6,  // 21: veriservice.GetRequest.key:type_name -> veriservice.DatumKey
+	5,  // 22: veriservice.GetResponse.datum:type_name -> veriservice.Datum
+	6,  // 23: veriservice.MultiGetRequest.key:type_name -> veriservice.DatumKey
+	22, // 24: veriservice.MultiGetResponse.result:type_name -> veriservice.GetResponse
+	5,  // 25: veriservice.FieldQueryResponse.datum:type_name -> veriservice.Datum
+	26, // 26: veriservice.FieldQueryResponse.values:type_name -> veriservice.FieldValueCount
+	30, // 27: veriservice.Peer.dataList:type_name -> veriservice.DataConfig
+	31, // 28: veriservice.JoinRequest.peer:type_name -> veriservice.Peer
+	31, // 29: veriservice.AddPeerRequest.peer:type_name -> veriservice.Peer
+	0,  // 30: veriservice.VeriService.Search:input_type -> veriservice.SearchRequest
+	12, // 31: veriservice.VeriService.Insert:input_type -> veriservice.InsertionRequest
+	32, // 32: veriservice.VeriService.Join:input_type -> veriservice.JoinRequest
+	34, // 33: veriservice.VeriService.AddPeer:input_type -> veriservice.AddPeerRequest
+	3,  // 34: veriservice.VeriService.DataStream:input_type -> veriservice.GetDataRequest
+	30, // 35: veriservice.VeriService.CreateDataIfNotExists:input_type -> veriservice.DataConfig
+	3,  // 36: veriservice.VeriService.GetDataInfo:input_type -> veriservice.GetDataRequest
+	0,  // 37: veriservice.VeriService.SearchStream:input_type -> veriservice.SearchRequest
+	36, // 38: veriservice.VeriService.Ping:input_type -> veriservice.PingRequest
+	12, // 39: veriservice.VeriService.Upsert:input_type -> veriservice.InsertionRequest
+	19, // 40: veriservice.VeriService.Delete:input_type -> veriservice.DeleteRequest
+	15, // 41: veriservice.VeriService.InsertBatch:input_type -> veriservice.InsertBatchRequest
+	12, // 42: veriservice.VeriService.InsertStream:input_type -> veriservice.InsertionRequest
+	21, // 43: veriservice.VeriService.Get:input_type -> veriservice.GetRequest
+	23, // 44: veriservice.VeriService.MultiGet:input_type -> veriservice.MultiGetRequest
+	25, // 45: veriservice.VeriService.ListByField:input_type -> veriservice.FieldQuery
+	10, // 46: veriservice.VeriService.Search:output_type -> veriservice.SearchResponse
+	14, // 47: veriservice.VeriService.Insert:output_type -> veriservice.InsertionResponse
+	33, // 48: veriservice.VeriService.Join:output_type -> veriservice.JoinResponse
+	35, // 49: veriservice.VeriService.AddPeer:output_type -> veriservice.AddPeerResponse
+	5,  // 50: veriservice.VeriService.DataStream:output_type -> veriservice.Datum
+	29, // 51: veriservice.VeriService.CreateDataIfNotExists:output_type -> veriservice.DataInfo
+	29, // 52: veriservice.VeriService.GetDataInfo:output_type -> veriservice.DataInfo
+	8,  // 53: veriservice.VeriService.SearchStream:output_type -> veriservice.ScoredDatum
+	37, // 54: veriservice.VeriService.Ping:output_type -> veriservice.PingResponse
+	18, // 55: veriservice.VeriService.Upsert:output_type -> veriservice.UpsertResponse
+	28, // 56: veriservice.VeriService.Delete:output_type -> veriservice.DeleteResponse
+	17, // 57: veriservice.VeriService.InsertBatch:output_type -> veriservice.InsertBatchResponse
+	17, // 58: veriservice.VeriService.InsertStream:output_type -> veriservice.InsertBatchResponse
+	22, // 59: veriservice.VeriService.Get:output_type -> veriservice.GetResponse
+	24, // 60: veriservice.VeriService.MultiGet:output_type -> veriservice.MultiGetResponse
+	27, // 61: veriservice.VeriService.ListByField:output_type -> veriservice.FieldQueryResponse
+	46, // [46:62] is the sub-list for method output_type
+	30, // [30:46] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_veriservice_proto_init() }
@@ -1788,8 +3365,164 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SearchConfig); i {
+		file_veriservice_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchContext); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Datum); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DatumKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DatumValue); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScoredDatum); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InsertDatumWithConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchMetadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InsertionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InsertConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InsertionResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1800,8 +3533,8 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SearchContext); i {
+		file_veriservice_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InsertBatchRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1812,8 +3545,8 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetDataRequest); i {
+		file_veriservice_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InsertStatus); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1824,8 +3557,8 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Datum); i {
+		file_veriservice_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InsertBatchResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1836,8 +3569,8 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DatumKey); i {
+		file_veriservice_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpsertResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1848,8 +3581,8 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DatumValue); i {
+		file_veriservice_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1860,8 +3593,8 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ScoredDatum); i {
+		file_veriservice_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteConfig); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1872,8 +3605,8 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InsertDatumWithConfig); i {
+		file_veriservice_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1884,8 +3617,8 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SearchResponse); i {
+		file_veriservice_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1896,8 +3629,8 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InsertionRequest); i {
+		file_veriservice_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MultiGetRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1908,8 +3641,8 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InsertConfig); i {
+		file_veriservice_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MultiGetResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1920,8 +3653,8 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InsertionResponse); i {
+		file_veriservice_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FieldQuery); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1932,7 +3665,43 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+		file_veriservice_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FieldValueCount); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FieldQueryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_veriservice_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DataInfo); i {
 			case 0:
 				return &v.state
@@ -1944,7 +3713,7 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+		file_veriservice_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DataConfig); i {
 			case 0:
 				return &v.state
@@ -1956,7 +3725,7 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+		file_veriservice_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Peer); i {
 			case 0:
 				return &v.state
@@ -1968,7 +3737,7 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+		file_veriservice_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*JoinRequest); i {
 			case 0:
 				return &v.state
@@ -1980,7 +3749,7 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+		file_veriservice_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*JoinResponse); i {
 			case 0:
 				return &v.state
@@ -1992,7 +3761,7 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+		file_veriservice_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AddPeerRequest); i {
 			case 0:
 				return &v.state
@@ -2004,7 +3773,7 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+		file_veriservice_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AddPeerResponse); i {
 			case 0:
 				return &v.state
@@ -2016,7 +3785,7 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+		file_veriservice_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*PingRequest); i {
 			case 0:
 				return &v.state
@@ -2028,7 +3797,7 @@ func file_veriservice_proto_init() {
 				return nil
 			}
 		}
-		file_veriservice_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+		file_veriservice_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*PingResponse); i {
 			case 0:
 				return &v.state
@@ -2047,7 +3816,7 @@ func file_veriservice_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_veriservice_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   22,
+			NumMessages:   38,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -2082,6 +3851,13 @@ type VeriServiceClient interface {
 	GetDataInfo(ctx context.Context, in *GetDataRequest, opts ...grpc.CallOption) (*DataInfo, error)
 	SearchStream(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (VeriService_SearchStreamClient, error)
 	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	Upsert(ctx context.Context, in *InsertionRequest, opts ...grpc.CallOption) (*UpsertResponse, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	InsertBatch(ctx context.Context, in *InsertBatchRequest, opts ...grpc.CallOption) (*InsertBatchResponse, error)
+	InsertStream(ctx context.Context, opts ...grpc.CallOption) (VeriService_InsertStreamClient, error)
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	MultiGet(ctx context.Context, in *MultiGetRequest, opts ...grpc.CallOption) (*MultiGetResponse, error)
+	ListByField(ctx context.Context, in *FieldQuery, opts ...grpc.CallOption) (*FieldQueryResponse, error)
 }
 
 type veriServiceClient struct {
@@ -2219,6 +3995,94 @@ func (c *veriServiceClient) Ping(ctx context.Context, in *PingRequest, opts ...g
 	return out, nil
 }
 
+func (c *veriServiceClient) Upsert(ctx context.Context, in *InsertionRequest, opts ...grpc.CallOption) (*UpsertResponse, error) {
+	out := new(UpsertResponse)
+	err := c.cc.Invoke(ctx, "/veriservice.VeriService/Upsert", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *veriServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/veriservice.VeriService/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *veriServiceClient) InsertBatch(ctx context.Context, in *InsertBatchRequest, opts ...grpc.CallOption) (*InsertBatchResponse, error) {
+	out := new(InsertBatchResponse)
+	err := c.cc.Invoke(ctx, "/veriservice.VeriService/InsertBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *veriServiceClient) InsertStream(ctx context.Context, opts ...grpc.CallOption) (VeriService_InsertStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_VeriService_serviceDesc.Streams[2], "/veriservice.VeriService/InsertStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &veriServiceInsertStreamClient{stream}
+	return x, nil
+}
+
+type VeriService_InsertStreamClient interface {
+	Send(*InsertionRequest) error
+	CloseAndRecv() (*InsertBatchResponse, error)
+	grpc.ClientStream
+}
+
+type veriServiceInsertStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *veriServiceInsertStreamClient) Send(m *InsertionRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *veriServiceInsertStreamClient) CloseAndRecv() (*InsertBatchResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(InsertBatchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *veriServiceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
+	out := new(GetResponse)
+	err := c.cc.Invoke(ctx, "/veriservice.VeriService/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *veriServiceClient) MultiGet(ctx context.Context, in *MultiGetRequest, opts ...grpc.CallOption) (*MultiGetResponse, error) {
+	out := new(MultiGetResponse)
+	err := c.cc.Invoke(ctx, "/veriservice.VeriService/MultiGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *veriServiceClient) ListByField(ctx context.Context, in *FieldQuery, opts ...grpc.CallOption) (*FieldQueryResponse, error) {
+	out := new(FieldQueryResponse)
+	err := c.cc.Invoke(ctx, "/veriservice.VeriService/ListByField", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // VeriServiceServer is the server API for VeriService service.
 type VeriServiceServer interface {
 	Search(context.Context, *SearchRequest) (*SearchResponse, error)
@@ -2230,6 +4094,13 @@ type VeriServiceServer interface {
 	GetDataInfo(context.Context, *GetDataRequest) (*DataInfo, error)
 	SearchStream(*SearchRequest, VeriService_SearchStreamServer) error
 	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	Upsert(context.Context, *InsertionRequest) (*UpsertResponse, error)
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	InsertBatch(context.Context, *InsertBatchRequest) (*InsertBatchResponse, error)
+	InsertStream(VeriService_InsertStreamServer) error
+	Get(context.Context, *GetRequest) (*GetResponse, error)
+	MultiGet(context.Context, *MultiGetRequest) (*MultiGetResponse, error)
+	ListByField(context.Context, *FieldQuery) (*FieldQueryResponse, error)
 }
 
 // UnimplementedVeriServiceServer can be embedded to have forward compatible implementations.
@@ -2263,6 +4134,27 @@ func (*UnimplementedVeriServiceServer) SearchStream(*SearchRequest, VeriService_
 func (*UnimplementedVeriServiceServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
+func (*UnimplementedVeriServiceServer) Upsert(context.Context, *InsertionRequest) (*UpsertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Upsert not implemented")
+}
+func (*UnimplementedVeriServiceServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (*UnimplementedVeriServiceServer) InsertBatch(context.Context, *InsertBatchRequest) (*InsertBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InsertBatch not implemented")
+}
+func (*UnimplementedVeriServiceServer) InsertStream(VeriService_InsertStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method InsertStream not implemented")
+}
+func (*UnimplementedVeriServiceServer) Get(context.Context, *GetRequest) (*GetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (*UnimplementedVeriServiceServer) MultiGet(context.Context, *MultiGetRequest) (*MultiGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MultiGet not implemented")
+}
+func (*UnimplementedVeriServiceServer) ListByField(context.Context, *FieldQuery) (*FieldQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListByField not implemented")
+}
 
 func RegisterVeriServiceServer(s *grpc.Server, srv VeriServiceServer) {
 	s.RegisterService(&_VeriService_serviceDesc, srv)
@@ -2436,6 +4328,140 @@ func _VeriService_Ping_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VeriService_Upsert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InsertionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VeriServiceServer).Upsert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/veriservice.VeriService/Upsert",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VeriServiceServer).Upsert(ctx, req.(*InsertionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VeriService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VeriServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/veriservice.VeriService/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VeriServiceServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VeriService_InsertBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InsertBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VeriServiceServer).InsertBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/veriservice.VeriService/InsertBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VeriServiceServer).InsertBatch(ctx, req.(*InsertBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VeriService_InsertStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(VeriServiceServer).InsertStream(&veriServiceInsertStreamServer{stream})
+}
+
+type VeriService_InsertStreamServer interface {
+	SendAndClose(*InsertBatchResponse) error
+	Recv() (*InsertionRequest, error)
+	grpc.ServerStream
+}
+
+type veriServiceInsertStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *veriServiceInsertStreamServer) SendAndClose(m *InsertBatchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *veriServiceInsertStreamServer) Recv() (*InsertionRequest, error) {
+	m := new(InsertionRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _VeriService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VeriServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/veriservice.VeriService/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VeriServiceServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VeriService_MultiGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VeriServiceServer).MultiGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/veriservice.VeriService/MultiGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VeriServiceServer).MultiGet(ctx, req.(*MultiGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VeriService_ListByField_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FieldQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VeriServiceServer).ListByField(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/veriservice.VeriService/ListByField",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VeriServiceServer).ListByField(ctx, req.(*FieldQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _VeriService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "veriservice.VeriService",
 	HandlerType: (*VeriServiceServer)(nil),
@@ -2468,6 +4494,30 @@ var _VeriService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Ping",
 			Handler:    _VeriService_Ping_Handler,
 		},
+		{
+			MethodName: "Upsert",
+			Handler:    _VeriService_Upsert_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _VeriService_Delete_Handler,
+		},
+		{
+			MethodName: "InsertBatch",
+			Handler:    _VeriService_InsertBatch_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _VeriService_Get_Handler,
+		},
+		{
+			MethodName: "MultiGet",
+			Handler:    _VeriService_MultiGet_Handler,
+		},
+		{
+			MethodName: "ListByField",
+			Handler:    _VeriService_ListByField_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2480,6 +4530,11 @@ var _VeriService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _VeriService_SearchStream_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "InsertStream",
+			Handler:       _VeriService_InsertStream_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "veriservice.proto",
 }